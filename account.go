@@ -0,0 +1,17 @@
+package routefusion
+
+import "context"
+
+// GetBalance returns the current balance of the authenticated user.
+func (a *API) GetBalance() (*BalanceResponse, error) {
+	return a.GetBalanceWithContext(context.Background())
+}
+
+// GetBalanceWithContext is like GetBalance but binds the request to ctx.
+func (a *API) GetBalanceWithContext(ctx context.Context) (*BalanceResponse, error) {
+	out := &BalanceResponse{}
+	if err := a.do(ctx, getBalanceEndpoint.operation(), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
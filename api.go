@@ -1,6 +1,9 @@
 package routefusion
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // Client specifies the abstraction for Routefusion APIs.
 type Client interface {
@@ -20,85 +23,121 @@ type Client interface {
 // Users specifies the user related tasks that can be performed using the sdk.
 type Users interface {
 	GetUser() (*UserDetails, error)
+	GetUserWithContext(ctx context.Context) (*UserDetails, error)
 	UpdateUser(*User) (*UpdatedUserDetails, error)
+	UpdateUserWithContext(ctx context.Context, user *User) (*UpdatedUserDetails, error)
 	GetUserMaster(subUserUUID string) (*AllUserDetails, error)
-	// TODO: Check pagination
-	ListUsersMaster() ([]AllUserDetails, error)
+	GetUserMasterWithContext(ctx context.Context, subUserUUID string) (*AllUserDetails, error)
+	ListUsersMaster(opts *ListOptions) ([]AllUserDetails, error)
+	ListUsersMasterWithContext(ctx context.Context, opts *ListOptions) ([]AllUserDetails, error)
 }
 
 // Beneficiaries specifies the operations that can be performed around benficiaries.
 type Beneficiaries interface {
-	// TODO: Check pagination
-	ListBeneficiaries() ([]Beneficiary, error)
+	ListBeneficiaries(opts *ListOptions) ([]Beneficiary, error)
+	ListBeneficiariesWithContext(ctx context.Context, opts *ListOptions) ([]Beneficiary, error)
 	GetBeneficiary(id string) (*BeneficiaryBase, error)
+	GetBeneficiaryWithContext(ctx context.Context, id string) (*BeneficiaryBase, error)
 	CreateBeneficiary(*BeneficiaryInput) (*BeneficiaryBase, error)
+	CreateBeneficiaryWithContext(ctx context.Context, body *BeneficiaryInput) (*BeneficiaryBase, error)
 	UpdateBeneficiary(id string, body *UpdateBeneficiaryInput) (*BeneficiaryBase, error)
+	UpdateBeneficiaryWithContext(ctx context.Context, id string, body *UpdateBeneficiaryInput) (*BeneficiaryBase, error)
 	GetSubUserBeneficiariesMaster(subuserID string) ([]Beneficiary, error)
+	GetSubUserBeneficiariesMasterWithContext(ctx context.Context, subuserID string) ([]Beneficiary, error)
 	GetSubUserBeneficiaryMaster(subuserID string, beneficiaryID string) (*BeneficiaryBase, error)
-	CreateSubUserBeneficiaryMaster(subUserID string) (*BeneficiaryBase, error)
-	UpdateSubUserBeneficiaryMaster(subUserID string, beneficiaryID string) (*BeneficiaryBase, error)
+	GetSubUserBeneficiaryMasterWithContext(ctx context.Context, subuserID string, beneficiaryID string) (*BeneficiaryBase, error)
+	CreateSubUserBeneficiaryMaster(subUserID string, body *BeneficiaryInput) (*BeneficiaryBase, error)
+	CreateSubUserBeneficiaryMasterWithContext(ctx context.Context, subUserID string, body *BeneficiaryInput) (*BeneficiaryBase, error)
+	UpdateSubUserBeneficiaryMaster(subUserID string, beneficiaryID string, body *UpdateBeneficiaryInput) (*BeneficiaryBase, error)
+	UpdateSubUserBeneficiaryMasterWithContext(ctx context.Context, subUserID string, beneficiaryID string, body *UpdateBeneficiaryInput) (*BeneficiaryBase, error)
 }
 
 // Quotes specifies the operations that can be performed around quotes.
 type Quotes interface {
 	CreateQuote(*QuoteInput) (*QuoteResponse, error)
+	CreateQuoteWithContext(ctx context.Context, body *QuoteInput) (*QuoteResponse, error)
 }
 
 // Transfers specifies the operations that can be performed around transfers.
 type Transfers interface {
 	CreateTransfer(*TransferInput) (*TransferResponse, error)
+	CreateTransferWithContext(ctx context.Context, body *TransferInput) (*TransferResponse, error)
 	GetTransfer(id string) (*TransferResponse, error)
+	GetTransferWithContext(ctx context.Context, id string) (*TransferResponse, error)
 	CancelTransfer(uuid string) (cancelledID string, err error)
-	CreateTransferMaster(uuid string) (*TransferState, error)
+	CancelTransferWithContext(ctx context.Context, uuid string) (cancelledID string, err error)
+	CreateTransferMaster(subUserID string, body *TransferInput) (*TransferState, error)
+	CreateTransferMasterWithContext(ctx context.Context, subUserID string, body *TransferInput) (*TransferState, error)
 	GetTransferMaster(subUserID, transferID string) (*TransferResponse, error)
+	GetTransferMasterWithContext(ctx context.Context, subUserID, transferID string) (*TransferResponse, error)
 	GetTransferStatusMaster(subUserID, transferID string) (*TransferState, error)
+	GetTransferStatusMasterWithContext(ctx context.Context, subUserID, transferID string) (*TransferState, error)
 	CancelTransferMaster(subUserID, transferID string) (cancelledID string, err error)
+	CancelTransferMasterWithContext(ctx context.Context, subUserID, transferID string) (cancelledID string, err error)
 }
 
 // BatchTransfers specifies the operations that can be performed on batch
 // transfers.
 type BatchTransfers interface {
 	CreateBatchPayment(payload io.ReadSeeker) (*BatchTransferStatus, error)
+	CreateBatchPaymentWithContext(ctx context.Context, payload io.ReadSeeker) (*BatchTransferStatus, error)
 	GetBatchPayment(batchID string) (*BatchTransferStatus, error)
+	GetBatchPaymentWithContext(ctx context.Context, batchID string) (*BatchTransferStatus, error)
 }
 
 // Transactions is an interface that specifies operations concerning reading
 // of transactions.
 type Transactions interface {
-	GetTransactions() ([]TransactionResponse, error)
+	GetTransactions(opts *ListOptions) ([]TransactionResponse, error)
+	GetTransactionsWithContext(ctx context.Context, opts *ListOptions) ([]TransactionResponse, error)
 }
 
 // Account dictates an interface for retrieving account reports.
 type Account interface {
 	GetBalance() (*BalanceResponse, error)
+	GetBalanceWithContext(ctx context.Context) (*BalanceResponse, error)
 }
 
 // Webhooks is an interface for webhook based operation.
 type Webhooks interface {
 	GetWebhook(id string) (*WebhookResponse, error)
+	GetWebhookWithContext(ctx context.Context, id string) (*WebhookResponse, error)
 	UpdateWebhook(id string, updateInput WebhookUpdateInput) (*WebhookResponse, error)
-	IndexWebhooks() ([]WebhookResponse, error)
+	UpdateWebhookWithContext(ctx context.Context, id string, updateInput WebhookUpdateInput) (*WebhookResponse, error)
+	IndexWebhooks(opts *ListOptions) ([]WebhookResponse, error)
+	IndexWebhooksWithContext(ctx context.Context, opts *ListOptions) ([]WebhookResponse, error)
 	CreateWebhook(createInput WebhookUpdateInput) (*WebhookResponse, error)
+	CreateWebhookWithContext(ctx context.Context, createInput WebhookUpdateInput) (*WebhookResponse, error)
 	DeleteWebhook(id string) error
+	DeleteWebhookWithContext(ctx context.Context, id string) error
 }
 
 // KYC is an interface for KYC based CRUD operations.
 type KYC interface {
-	// TODO: Solve this problem, struct vs io.ReadSeeker
 	CreateKYC(subUserID string, kycBody KYCBody) error
+	CreateKYCWithContext(ctx context.Context, subUserID string, kycBody KYCBody) error
+	CreateKYCWithDocuments(subUserID string, kycBody KYCBody, documents ...KYCDocument) error
+	CreateKYCWithDocumentsWithContext(ctx context.Context, subUserID string, kycBody KYCBody,
+		documents ...KYCDocument) error
 	ShowKYC(subUserID string) (*KYCDetails, error)
+	ShowKYCWithContext(ctx context.Context, subUserID string) (*KYCDetails, error)
 	UpdateUserKYC(subUserID string, kycBody KYCBody) error
+	UpdateUserKYCWithContext(ctx context.Context, subUserID string, kycBody KYCBody) error
+	UpdateUserKYCWithDocuments(subUserID string, kycBody KYCBody, documents ...KYCDocument) error
+	UpdateUserKYCWithDocumentsWithContext(ctx context.Context, subUserID string, kycBody KYCBody,
+		documents ...KYCDocument) error
 	DeleteKYC(subUserID string) error
+	DeleteKYCWithContext(ctx context.Context, subUserID string) error
 }
 
 // CurrencyCoverage is an interface for currency based transactions.
 type CurrencyCoverage interface {
-	// TODO: Interface{} is because I cant make sense of the response to
-	// this endpoint. Need to fix.
-	GetCurrencies() (interface{}, error)
+	GetCurrencies() (*CurrenciesResponse, error)
+	GetCurrenciesWithContext(ctx context.Context) (*CurrenciesResponse, error)
 }
 
 // WireInstructions is an interface for wireinstruction based operations.
 type WireInstructions interface {
 	GetWireInstructions(currencyCode string) ([]PaymentInstructions, error)
+	GetWireInstructionsWithContext(ctx context.Context, currencyCode string) ([]PaymentInstructions, error)
 }
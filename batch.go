@@ -0,0 +1,38 @@
+package routefusion
+
+import (
+	"context"
+	"io"
+)
+
+// CreateBatchPayment uploads a batch payment file.
+func (a *API) CreateBatchPayment(payload io.ReadSeeker) (*BatchTransferStatus, error) {
+	return a.CreateBatchPaymentWithContext(context.Background(), payload)
+}
+
+// CreateBatchPaymentWithContext is like CreateBatchPayment but binds the
+// request to ctx.
+func (a *API) CreateBatchPaymentWithContext(ctx context.Context,
+	payload io.ReadSeeker) (*BatchTransferStatus, error) {
+	out := &BatchTransferStatus{}
+	if err := a.do(ctx, createBatchPaymentEndpoint.operation(), payload, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetBatchPayment returns the status of a batch payment.
+func (a *API) GetBatchPayment(batchID string) (*BatchTransferStatus, error) {
+	return a.GetBatchPaymentWithContext(context.Background(), batchID)
+}
+
+// GetBatchPaymentWithContext is like GetBatchPayment but binds the request to
+// ctx.
+func (a *API) GetBatchPaymentWithContext(ctx context.Context,
+	batchID string) (*BatchTransferStatus, error) {
+	out := &BatchTransferStatus{}
+	if err := a.do(ctx, getBatchPaymentEndpoint.operation(batchID), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
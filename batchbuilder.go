@@ -0,0 +1,288 @@
+package routefusion
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBatchRows is the number of rows a batch holds when
+// BatchConfig.MaxRows is not set.
+const DefaultMaxBatchRows = 1000
+
+// BatchFormat is the format of a batch payment file.
+type BatchFormat string
+
+// Batch payment file formats. CSV files start with a header naming the
+// columns, JSON files are an array of rows.
+const (
+	BatchCSV  BatchFormat = "csv"
+	BatchJSON BatchFormat = "json"
+)
+
+// batchColumns are the columns of CSV batch files, in the order they are
+// written.
+var batchColumns = []string{"beneficiary_id", "amount", "currency", "reference"}
+
+// Errors returned when building or parsing batches.
+var (
+	ErrBatchFull  = errors.New("batch has reached its maximum number of rows")
+	ErrBatchEmpty = errors.New("batch has no rows")
+)
+
+// BatchRow is a transfer of a batch payment.
+type BatchRow struct {
+	BeneficiaryID int `json:"beneficiary_id"`
+
+	// Amount is paid to the beneficiary in Currency.
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+
+	Reference string `json:"reference,omitempty"`
+}
+
+// Validate checks the row before it is added to a batch. It returns a
+// *ValidationError listing the invalid fields.
+func (r *BatchRow) Validate() error {
+	v := &validator{}
+	if r.BeneficiaryID <= 0 {
+		v.fail("beneficiary_id", "is required")
+	}
+	if r.Amount.Sign() <= 0 {
+		v.fail("amount", "must be positive")
+	}
+	if v.require("currency", r.Currency) {
+		if len(r.Currency) != 3 {
+			v.fail("currency", "must be an ISO 4217 code")
+		} else if !r.Amount.Equal(r.Amount.RoundToCurrency(r.Currency)) {
+			v.fail("amount", fmt.Sprintf("has more decimals than %s allows",
+				strings.ToUpper(r.Currency)))
+		}
+	}
+	return v.err()
+}
+
+// BatchConfig configures a BatchBuilder and ParseBatch.
+type BatchConfig struct {
+	// Format is the format of the file, BatchCSV if empty.
+	Format BatchFormat
+
+	// MaxRows is the number of rows the batch can hold, DefaultMaxBatchRows
+	// if zero.
+	MaxRows int
+}
+
+func (c BatchConfig) withDefaults() BatchConfig {
+	if c.Format == "" {
+		c.Format = BatchCSV
+	}
+	if c.MaxRows <= 0 {
+		c.MaxRows = DefaultMaxBatchRows
+	}
+	return c
+}
+
+// BatchFile is a batch payment file returned by BatchBuilder.Build. It is
+// uploaded with the Content-Type of its format.
+type BatchFile struct {
+	*bytes.Reader
+	Format BatchFormat
+}
+
+// ContentType returns the MIME type of the format of the file.
+func (f *BatchFile) ContentType() string {
+	if f.Format == BatchJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// BatchBuilder builds the file of a batch payment, to be uploaded with
+// CreateBatchPayment.
+type BatchBuilder struct {
+	config BatchConfig
+	rows   []BatchRow
+}
+
+// NewBatchBuilder returns an empty BatchBuilder.
+func NewBatchBuilder(config BatchConfig) *BatchBuilder {
+	return &BatchBuilder{config: config.withDefaults()}
+}
+
+// Add validates row and adds it to the batch. It returns ErrBatchFull if
+// the batch cannot hold more rows.
+func (b *BatchBuilder) Add(row BatchRow) error {
+	if len(b.rows) >= b.config.MaxRows {
+		return ErrBatchFull
+	}
+	if err := row.Validate(); err != nil {
+		return err
+	}
+	row.Currency = strings.ToUpper(row.Currency)
+	b.rows = append(b.rows, row)
+	return nil
+}
+
+// Len returns the number of rows of the batch.
+func (b *BatchBuilder) Len() int {
+	return len(b.rows)
+}
+
+// Rows returns the rows of the batch.
+func (b *BatchBuilder) Rows() []BatchRow {
+	return append([]BatchRow(nil), b.rows...)
+}
+
+// Build serializes the batch. It returns ErrBatchEmpty if no row was added.
+func (b *BatchBuilder) Build() (*BatchFile, error) {
+	if len(b.rows) == 0 {
+		return nil, ErrBatchEmpty
+	}
+
+	buf := &bytes.Buffer{}
+	switch b.config.Format {
+	case BatchCSV:
+		w := csv.NewWriter(buf)
+		if err := w.Write(batchColumns); err != nil {
+			return nil, err
+		}
+		for _, row := range b.rows {
+			err := w.Write([]string{strconv.Itoa(row.BeneficiaryID), row.Amount.String(),
+				row.Currency, row.Reference})
+			if err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+	case BatchJSON:
+		if err := json.NewEncoder(buf).Encode(b.rows); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown batch format %q", b.config.Format)
+	}
+	return &BatchFile{Reader: bytes.NewReader(buf.Bytes()), Format: b.config.Format}, nil
+}
+
+// ParseBatch reads the rows of a batch file in config.Format, validating
+// every row. It returns ErrBatchFull, without reading the rest of the file,
+// once the file has more than config.MaxRows rows.
+func ParseBatch(r io.Reader, config BatchConfig) ([]BatchRow, error) {
+	config = config.withDefaults()
+
+	var rows []BatchRow
+	var err error
+	switch config.Format {
+	case BatchCSV:
+		rows, err = parseBatchCSV(r, config.MaxRows)
+	case BatchJSON:
+		rows, err = parseBatchJSON(r, config.MaxRows)
+	default:
+		err = fmt.Errorf("unknown batch format %q", config.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		if err := rows[i].Validate(); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+	return rows, nil
+}
+
+func parseBatchJSON(r io.Reader, maxRows int) ([]BatchRow, error) {
+	dec := json.NewDecoder(r)
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, nil
+	}
+	if t != json.Delim('[') {
+		return nil, errors.New("batch file is not a JSON array")
+	}
+
+	var rows []BatchRow
+	for dec.More() {
+		if len(rows) >= maxRows {
+			return nil, ErrBatchFull
+		}
+		var row BatchRow
+		if err := dec.Decode(&row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func parseBatchCSV(r io.Reader, maxRows int) ([]BatchRow, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	for _, name := range batchColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var rows []BatchRow
+	for i := 0; ; i++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if i >= maxRows {
+			return nil, ErrBatchFull
+		}
+
+		value := func(name string) string {
+			if j, ok := columns[name]; ok {
+				return strings.TrimSpace(record[j])
+			}
+			return ""
+		}
+
+		var row BatchRow
+		if id := value("beneficiary_id"); id != "" {
+			if row.BeneficiaryID, err = strconv.Atoi(id); err != nil {
+				return nil, fmt.Errorf("row %d: invalid beneficiary_id %q", i+1, id)
+			}
+		}
+		if amount := value("amount"); amount != "" {
+			if row.Amount, err = ParseDecimal(amount); err != nil {
+				return nil, fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		row.Currency = value("currency")
+		row.Reference = value("reference")
+		rows = append(rows, row)
+	}
+}
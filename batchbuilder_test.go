@@ -0,0 +1,221 @@
+package routefusion
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/routefusion/routefusion-golang/client"
+	"github.com/stretchr/testify/assert"
+)
+
+var testBatchRows = []BatchRow{
+	{BeneficiaryID: 1, Amount: MustParseDecimal("100.50"), Currency: "USD",
+		Reference: "invoice 1, March"},
+	{BeneficiaryID: 2, Amount: MustParseDecimal("2500"), Currency: "JPY"},
+}
+
+func Test_BatchBuilder(t *testing.T) {
+	testCases := []struct {
+		format              BatchFormat
+		expected            string
+		expectedContentType string
+	}{
+		{
+			format:              BatchCSV,
+			expectedContentType: "text/csv",
+			expected: "beneficiary_id,amount,currency,reference\n" +
+				"1,100.50,USD,\"invoice 1, March\"\n" +
+				"2,2500,JPY,\n",
+		},
+		{
+			format:              BatchJSON,
+			expectedContentType: "application/json",
+			expected: `[{"beneficiary_id":1,"amount":100.50,"currency":"USD",` +
+				`"reference":"invoice 1, March"},` +
+				`{"beneficiary_id":2,"amount":2500,"currency":"JPY"}]` + "\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.format), func(t *testing.T) {
+			b := NewBatchBuilder(BatchConfig{Format: testCase.format})
+			for _, row := range testBatchRows {
+				if err := b.Add(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			assert.Equal(t, 2, b.Len())
+
+			payload, err := b.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, testCase.expectedContentType, payload.ContentType())
+			data, err := ioutil.ReadAll(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, testCase.expected, string(data))
+
+			rows, err := ParseBatch(strings.NewReader(string(data)),
+				BatchConfig{Format: testCase.format})
+			if assert.NoError(t, err) {
+				assert.Equal(t, len(testBatchRows), len(rows))
+				for i := range rows {
+					assertBatchRow(t, testBatchRows[i], rows[i])
+				}
+			}
+		})
+	}
+}
+
+func assertBatchRow(t *testing.T, expected, actual BatchRow) {
+	t.Helper()
+	assert.True(t, expected.Amount.Equal(actual.Amount), "expected amount %s, got %s",
+		expected.Amount, actual.Amount)
+	expected.Amount, actual.Amount = Decimal{}, Decimal{}
+	assert.Equal(t, expected, actual)
+}
+
+func Test_BatchBuilderLimits(t *testing.T) {
+	b := NewBatchBuilder(BatchConfig{MaxRows: 1})
+	_, err := b.Build()
+	assert.Equal(t, ErrBatchEmpty, err)
+
+	assert.NoError(t, b.Add(testBatchRows[0]))
+	assert.Equal(t, ErrBatchFull, b.Add(testBatchRows[1]))
+	assert.Equal(t, testBatchRows[:1], b.Rows())
+
+	xml := NewBatchBuilder(BatchConfig{Format: "xml"})
+	assert.NoError(t, xml.Add(testBatchRows[0]))
+	_, err = xml.Build()
+	assert.EqualError(t, err, `unknown batch format "xml"`)
+}
+
+func Test_BatchRowValidate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		row      BatchRow
+		expected []client.FieldError
+	}{
+		{
+			desc: "valid",
+			row:  BatchRow{BeneficiaryID: 1, Amount: MustParseDecimal("1.5"), Currency: "usd"},
+		},
+		{
+			desc: "missing fields",
+			row:  BatchRow{},
+			expected: []client.FieldError{
+				{Field: "beneficiary_id", Message: "is required"},
+				{Field: "amount", Message: "must be positive"},
+				{Field: "currency", Message: "is required"},
+			},
+		},
+		{
+			desc: "negative amount",
+			row:  BatchRow{BeneficiaryID: 1, Amount: MustParseDecimal("-1"), Currency: "EUR"},
+			expected: []client.FieldError{
+				{Field: "amount", Message: "must be positive"},
+			},
+		},
+		{
+			desc: "too many decimals",
+			row:  BatchRow{BeneficiaryID: 1, Amount: MustParseDecimal("1.5"), Currency: "JPY"},
+			expected: []client.FieldError{
+				{Field: "amount", Message: "has more decimals than JPY allows"},
+			},
+		},
+		{
+			desc: "invalid currency",
+			row:  BatchRow{BeneficiaryID: 1, Amount: MustParseDecimal("1"), Currency: "EURO"},
+			expected: []client.FieldError{
+				{Field: "currency", Message: "must be an ISO 4217 code"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			err := testCase.row.Validate()
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.IsType(t, &ValidationError{}, err) {
+				assert.Equal(t, testCase.expected, err.(*ValidationError).Fields)
+			}
+		})
+	}
+}
+
+func Test_ParseBatchErrors(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		payload        string
+		format         BatchFormat
+		maxRows        int
+		expectedErrMsg string
+	}{
+		{
+			desc:           "missing column",
+			payload:        "beneficiary_id,amount\n1,10\n",
+			format:         BatchCSV,
+			expectedErrMsg: `missing column "currency"`,
+		},
+		{
+			desc:           "invalid beneficiary",
+			payload:        "beneficiary_id,amount,currency\nbob,10,USD\n",
+			format:         BatchCSV,
+			expectedErrMsg: `row 1: invalid beneficiary_id "bob"`,
+		},
+		{
+			desc:           "invalid amount",
+			payload:        "beneficiary_id,amount,currency\n1,ten,USD\n",
+			format:         BatchCSV,
+			expectedErrMsg: `row 1: invalid decimal "ten"`,
+		},
+		{
+			desc:           "invalid row",
+			payload:        `[{"beneficiary_id": 1, "amount": 1, "currency": "USD"}, {"amount": 1}]`,
+			format:         BatchJSON,
+			expectedErrMsg: "row 2: invalid input: beneficiary_id is required, currency is required",
+		},
+		{
+			desc:           "not an array",
+			payload:        `{"beneficiary_id": 1}`,
+			format:         BatchJSON,
+			expectedErrMsg: "batch file is not a JSON array",
+		},
+		{
+			desc:           "too many csv rows",
+			payload:        "beneficiary_id,amount,currency\n1,10,USD\n2,10,USD\n",
+			format:         BatchCSV,
+			maxRows:        1,
+			expectedErrMsg: ErrBatchFull.Error(),
+		},
+		{
+			desc: "too many json rows",
+			payload: `[{"beneficiary_id": 1, "amount": 1, "currency": "USD"},
+				{"beneficiary_id": 2, "amount": 1, "currency": "USD"}]`,
+			format:         BatchJSON,
+			maxRows:        1,
+			expectedErrMsg: ErrBatchFull.Error(),
+		},
+		{
+			desc:           "unknown format",
+			format:         "xml",
+			expectedErrMsg: `unknown batch format "xml"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			_, err := ParseBatch(strings.NewReader(testCase.payload),
+				BatchConfig{Format: testCase.format, MaxRows: testCase.maxRows})
+			if assert.Error(t, err) {
+				assert.Equal(t, testCase.expectedErrMsg, err.Error())
+			}
+		})
+	}
+}
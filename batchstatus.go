@@ -0,0 +1,296 @@
+package routefusion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Status returns the state of the row. Rows rejected before their transfer
+// was created are failed, rows not processed yet are created.
+func (i *BatchItem) Status() TransferStatus {
+	switch {
+	case i.State != "":
+		return i.State
+	case i.Error != "":
+		return TransferFailed
+	}
+	return TransferCreated
+}
+
+// Done reports whether every row of the batch reached a terminal state. A
+// batch reported without rows is done when its own status is terminal, e.g.
+// completed, or failed when it is rejected as a whole.
+func (b *BatchTransferStatus) Done() bool {
+	if len(b.Items) == 0 {
+		return TransferStatus(b.Status).IsTerminal()
+	}
+	for i := range b.Items {
+		if !b.Items[i].Status().IsTerminal() {
+			return false
+		}
+	}
+	return true
+}
+
+// progress identifies the state of every row, to tell polls apart.
+func (b *BatchTransferStatus) progress() string {
+	states := make([]string, len(b.Items))
+	for i := range b.Items {
+		states[i] = string(b.Items[i].Status())
+	}
+	return b.Status + ":" + strings.Join(states, ",")
+}
+
+// BatchSummary counts the rows of a batch by state and currency.
+type BatchSummary struct {
+	BatchUUID string
+	Rows      int
+
+	// Lines are sorted by state, in lifecycle order, then by currency.
+	Lines []BatchSummaryLine
+
+	// Failed are the failed rows.
+	Failed []BatchItem
+}
+
+// BatchSummaryLine counts the rows of a batch in a state and a currency.
+type BatchSummaryLine struct {
+	State    TransferStatus
+	Currency string
+	Count    int
+	Total    Decimal
+}
+
+// transferStatusOrder orders the states in summaries.
+var transferStatusOrder = map[TransferStatus]int{
+	TransferCreated: 1, TransferPending: 2, TransferVerifying: 3,
+	TransferProcessing: 4, TransferSent: 5, TransferCompleted: 6,
+	TransferFailed: 7, TransferCancelled: 8, TransferReturned: 9,
+}
+
+// Summary returns the counts and totals of the rows of the batch by state
+// and currency.
+func (b *BatchTransferStatus) Summary() *BatchSummary {
+	s := &BatchSummary{BatchUUID: b.UUID, Rows: len(b.Items)}
+	lines := map[BatchSummaryLine]int{}
+	for _, item := range b.Items {
+		key := BatchSummaryLine{State: item.Status(), Currency: strings.ToUpper(item.Currency)}
+		i, ok := lines[key]
+		if !ok {
+			i = len(s.Lines)
+			lines[key] = i
+			s.Lines = append(s.Lines, key)
+		}
+		s.Lines[i].Count++
+		s.Lines[i].Total = s.Lines[i].Total.Add(item.Amount)
+
+		if item.Status().IsFailed() {
+			s.Failed = append(s.Failed, item)
+		}
+	}
+
+	sort.Slice(s.Lines, func(i, j int) bool {
+		a, b := s.Lines[i], s.Lines[j]
+		if ra, rb := statusRank(a.State), statusRank(b.State); ra != rb {
+			return ra < rb
+		}
+		if a.State != b.State {
+			return a.State < b.State
+		}
+		return a.Currency < b.Currency
+	})
+	return s
+}
+
+// statusRank orders the states in lifecycle order, unknown ones last.
+func statusRank(s TransferStatus) int {
+	if rank, ok := transferStatusOrder[s]; ok {
+		return rank
+	}
+	return len(transferStatusOrder) + 1
+}
+
+// Count returns the number of rows in state.
+func (s *BatchSummary) Count(state TransferStatus) int {
+	count := 0
+	for _, line := range s.Lines {
+		if line.State == state {
+			count += line.Count
+		}
+	}
+	return count
+}
+
+// Total returns the amount of the rows in state and currency.
+func (s *BatchSummary) Total(state TransferStatus, currency string) Decimal {
+	for _, line := range s.Lines {
+		if line.State == state && strings.EqualFold(line.Currency, currency) {
+			return line.Total
+		}
+	}
+	return Decimal{}
+}
+
+// String formats the summary as a report with a table of the lines followed
+// by the failed rows.
+func (s *BatchSummary) String() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "batch %s: %d rows, %d failed\n", s.BatchUUID, s.Rows, len(s.Failed))
+
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATE\tCURRENCY\tCOUNT\tTOTAL")
+	for _, line := range s.Lines {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", line.State, line.Currency, line.Count,
+			line.Total.StringFixed(CurrencyMinorUnits(line.Currency)))
+	}
+	w.Flush()
+
+	for _, item := range s.Failed {
+		fmt.Fprintf(&buf, "row %d: %s", item.Row, item.Status())
+		if item.Error != "" {
+			fmt.Fprintf(&buf, ": %s", item.Error)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// BatchWaitOptions configures WaitForBatch. The zero value polls every
+// second, backing off up to every thirty seconds, for as long as the context
+// allows.
+type BatchWaitOptions struct {
+	// Timeout bounds the wait in addition to the deadline of the context.
+	Timeout time.Duration
+
+	// InitialInterval is the delay between polls after a row changed state.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between polls.
+	MaxInterval time.Duration
+
+	// Multiplier grows the delay after every poll that saw no change.
+	Multiplier float64
+
+	// OnProgress, when set, is called with the batch every time a row is
+	// seen to change state, starting with the first poll.
+	OnProgress func(b *BatchTransferStatus)
+}
+
+func (o *BatchWaitOptions) withDefaults() BatchWaitOptions {
+	var opts BatchWaitOptions
+	if o != nil {
+		opts = *o
+	}
+	wait := (&WaitOptions{
+		InitialInterval: opts.InitialInterval,
+		MaxInterval:     opts.MaxInterval,
+		Multiplier:      opts.Multiplier,
+	}).withDefaults()
+	opts.InitialInterval = wait.InitialInterval
+	opts.MaxInterval = wait.MaxInterval
+	opts.Multiplier = wait.Multiplier
+	return opts
+}
+
+// BatchWaitTimeoutError is returned by WaitForBatch when some rows of the
+// batch did not reach a terminal state in time.
+type BatchWaitTimeoutError struct {
+	// BatchID is the batch waited for.
+	BatchID string
+
+	// Last is the last version of the batch seen, nil if no poll succeeded.
+	Last *BatchTransferStatus
+
+	// Waited is how long the wait lasted.
+	Waited time.Duration
+
+	err error
+}
+
+func (e *BatchWaitTimeoutError) Error() string {
+	pending := "unknown"
+	if e.Last != nil {
+		s := e.Last.Summary()
+		done := 0
+		for _, line := range s.Lines {
+			if line.State.IsTerminal() {
+				done += line.Count
+			}
+		}
+		pending = fmt.Sprintf("%d of %d", s.Rows-done, s.Rows)
+	}
+	return fmt.Sprintf("timed out after %s waiting for batch %s, rows pending: %s",
+		e.Waited.Round(time.Millisecond), e.BatchID, pending)
+}
+
+// Timeout reports that the error is a timeout.
+func (e *BatchWaitTimeoutError) Timeout() bool {
+	return true
+}
+
+// Unwrap returns the context error that ended the wait.
+func (e *BatchWaitTimeoutError) Unwrap() error {
+	return e.err
+}
+
+// WaitForBatch polls a batch until every row reached a terminal state, and
+// returns it. nil opts selects the defaults.
+//
+// The wait ends with a *BatchWaitTimeoutError when the timeout of opts or
+// the deadline of ctx is reached, with the error of ctx when ctx is
+// cancelled, and with the error of the poll when one fails after the client
+// retries.
+func (a *API) WaitForBatch(ctx context.Context, id string,
+	opts *BatchWaitOptions) (*BatchTransferStatus, error) {
+	o := opts.withDefaults()
+	start := time.Now()
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	var last *BatchTransferStatus
+	b := &backoff{initial: o.InitialInterval, max: o.MaxInterval, multiplier: o.Multiplier}
+	for {
+		batch, err := a.GetBatchPaymentWithContext(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, batchWaitError(ctx, id, last, start)
+			}
+			return nil, err
+		}
+
+		changed := last == nil || batch.progress() != last.progress()
+		if changed && o.OnProgress != nil {
+			o.OnProgress(batch)
+		}
+		last = batch
+
+		if batch.Done() {
+			return batch, nil
+		}
+		if !b.sleep(ctx, changed) {
+			return nil, batchWaitError(ctx, id, last, start)
+		}
+	}
+}
+
+// batchWaitError returns the error ending a wait whose context is done.
+func batchWaitError(ctx context.Context, id string, last *BatchTransferStatus,
+	start time.Time) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx.Err()
+	}
+	return &BatchWaitTimeoutError{
+		BatchID: id,
+		Last:    last,
+		Waited:  time.Since(start),
+		err:     ctx.Err(),
+	}
+}
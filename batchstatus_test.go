@@ -0,0 +1,147 @@
+package routefusion
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testBatch = &BatchTransferStatus{UUID: "batch-1", Status: "processing", Items: []BatchItem{
+	{Row: 1, TransferUUID: "transfer-1", Amount: MustParseDecimal("100"), Currency: "MXN",
+		State: TransferCompleted},
+	{Row: 2, Amount: MustParseDecimal("10.5"), Currency: "usd", Error: "unknown beneficiary"},
+	{Row: 3, TransferUUID: "transfer-3", Amount: MustParseDecimal("200.25"), Currency: "MXN",
+		State: TransferCompleted},
+	{Row: 4, TransferUUID: "transfer-4", Amount: MustParseDecimal("5"), Currency: "EUR",
+		State: TransferReturned, Error: "account closed"},
+	{Row: 5, TransferUUID: "transfer-5", Amount: MustParseDecimal("7"), Currency: "EUR",
+		State: TransferProcessing},
+}}
+
+func Test_BatchSummary(t *testing.T) {
+	s := testBatch.Summary()
+
+	assert.Equal(t, 5, s.Rows)
+	assert.Equal(t, 2, s.Count(TransferCompleted))
+	assert.Equal(t, 0, s.Count(TransferCancelled))
+	assert.Equal(t, "300.25", s.Total(TransferCompleted, "mxn").String())
+	assert.True(t, s.Total(TransferCompleted, "USD").IsZero())
+	if assert.Len(t, s.Failed, 2) {
+		assert.Equal(t, 2, s.Failed[0].Row)
+		assert.Equal(t, 4, s.Failed[1].Row)
+	}
+
+	assert.Equal(t, "batch batch-1: 5 rows, 2 failed\n"+
+		"STATE       CURRENCY  COUNT  TOTAL\n"+
+		"processing  EUR       1      7.00\n"+
+		"completed   MXN       2      300.25\n"+
+		"failed      USD       1      10.50\n"+
+		"returned    EUR       1      5.00\n"+
+		"row 2: failed: unknown beneficiary\n"+
+		"row 4: returned: account closed\n", s.String())
+}
+
+func Test_BatchTransferStatusDone(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		batch    BatchTransferStatus
+		expected bool
+	}{
+		{desc: "rows pending", batch: *testBatch},
+		{desc: "no rows yet", batch: BatchTransferStatus{Status: "processing"}},
+		{desc: "rejected", batch: BatchTransferStatus{Status: "failed"}, expected: true},
+		{desc: "completed without rows", batch: BatchTransferStatus{Status: "completed"}, expected: true},
+		{desc: "cancelled without rows", batch: BatchTransferStatus{Status: "cancelled"}, expected: true},
+		{
+			desc: "every row terminal",
+			batch: BatchTransferStatus{Items: []BatchItem{
+				{State: TransferCompleted}, {Error: "invalid amount"}, {State: TransferCancelled},
+			}},
+			expected: true,
+		},
+		{
+			desc:  "row not processed yet",
+			batch: BatchTransferStatus{Items: []BatchItem{{State: TransferCompleted}, {}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.batch.Done())
+		})
+	}
+}
+
+// scriptedBatch answers with the given bodies one poll after the other,
+// repeating the last one.
+func scriptedBatch(bodies []string, polls *int) http.HandlerFunc {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body := bodies[len(bodies)-1]
+		if *polls < len(bodies) {
+			body = bodies[*polls]
+		}
+		*polls++
+		w.Write([]byte(body))
+	}
+}
+
+func Test_WaitForBatch(t *testing.T) {
+	var polls int
+	a, ts := newTestAPI(scriptedBatch([]string{
+		`{"uuid": "batch-1", "status": "processing"}`,
+		`{"uuid": "batch-1", "items": [{"row": 1, "state": "processing"}, {"row": 2, "state": "processing"}]}`,
+		`{"uuid": "batch-1", "items": [{"row": 1, "state": "processing"}, {"row": 2, "state": "processing"}]}`,
+		`{"uuid": "batch-1", "items": [{"row": 1, "state": "completed"}, {"row": 2, "state": "processing"}]}`,
+		`{"uuid": "batch-1", "items": [{"row": 1, "state": "completed"}, {"row": 2, "state": "failed"}]}`,
+	}, &polls))
+	defer ts.Close()
+
+	var progress []int
+	batch, err := a.WaitForBatch(context.Background(), "batch-1", &BatchWaitOptions{
+		InitialInterval: time.Millisecond,
+		OnProgress: func(b *BatchTransferStatus) {
+			progress = append(progress, b.Summary().Count(TransferProcessing))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 5, polls)
+	assert.Equal(t, []int{0, 2, 1, 0}, progress)
+	assert.Equal(t, TransferFailed, batch.Items[1].State)
+}
+
+func Test_WaitForBatchTimeout(t *testing.T) {
+	var polls int
+	a, ts := newTestAPI(scriptedBatch([]string{
+		`{"uuid": "batch-1", "items": [{"row": 1, "state": "completed"}, {"row": 2, "state": "sent"}]}`,
+	}, &polls))
+	defer ts.Close()
+
+	batch, err := a.WaitForBatch(context.Background(), "batch-1",
+		&BatchWaitOptions{InitialInterval: time.Millisecond, Timeout: 30 * time.Millisecond})
+	assert.Nil(t, batch)
+
+	timeoutErr, ok := err.(*BatchWaitTimeoutError)
+	if !assert.True(t, ok, "unexpected error %v", err) {
+		return
+	}
+	assert.True(t, timeoutErr.Timeout())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "batch-1", timeoutErr.BatchID)
+	assert.Contains(t, err.Error(), "rows pending: 1 of 2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = a.WaitForBatch(ctx, "batch-1", nil)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,140 @@
+package routefusion
+
+import "context"
+
+// ListBeneficiaries lists a page of the beneficiaries of the authenticated user.
+// The page is selected by opts, nil selects the first one.
+func (a *API) ListBeneficiaries(opts *ListOptions) ([]Beneficiary, error) {
+	return a.ListBeneficiariesWithContext(context.Background(), opts)
+}
+
+// ListBeneficiariesWithContext is like ListBeneficiaries but binds the request to ctx.
+func (a *API) ListBeneficiariesWithContext(ctx context.Context,
+	opts *ListOptions) ([]Beneficiary, error) {
+	var out []Beneficiary
+	if _, err := a.list(ctx, listBeneficiariesEndpoint.operation(), opts, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetBeneficiary returns a single beneficiary of the authenticated user.
+func (a *API) GetBeneficiary(id string) (*BeneficiaryBase, error) {
+	return a.GetBeneficiaryWithContext(context.Background(), id)
+}
+
+// GetBeneficiaryWithContext is like GetBeneficiary but binds the request to
+// ctx.
+func (a *API) GetBeneficiaryWithContext(ctx context.Context,
+	id string) (*BeneficiaryBase, error) {
+	out := &BeneficiaryBase{}
+	if err := a.do(ctx, getBeneficiaryEndpoint.operation(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateBeneficiary creates a beneficiary for the authenticated user.
+func (a *API) CreateBeneficiary(body *BeneficiaryInput) (*BeneficiaryBase, error) {
+	return a.CreateBeneficiaryWithContext(context.Background(), body)
+}
+
+// CreateBeneficiaryWithContext is like CreateBeneficiary but binds the request
+// to ctx.
+func (a *API) CreateBeneficiaryWithContext(ctx context.Context,
+	body *BeneficiaryInput) (*BeneficiaryBase, error) {
+	out := &BeneficiaryBase{}
+	if err := a.do(ctx, createBeneficiaryEndpoint.operation(), body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateBeneficiary updates a beneficiary of the authenticated user.
+func (a *API) UpdateBeneficiary(id string, body *UpdateBeneficiaryInput) (*BeneficiaryBase, error) {
+	return a.UpdateBeneficiaryWithContext(context.Background(), id, body)
+}
+
+// UpdateBeneficiaryWithContext is like UpdateBeneficiary but binds the request
+// to ctx.
+func (a *API) UpdateBeneficiaryWithContext(ctx context.Context,
+	id string, body *UpdateBeneficiaryInput) (*BeneficiaryBase, error) {
+	out := &BeneficiaryBase{}
+	if err := a.do(ctx, updateBeneficiaryEndpoint.operation(id), body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubUserBeneficiariesMaster lists the beneficiaries of a sub user.
+func (a *API) GetSubUserBeneficiariesMaster(subUserID string) ([]Beneficiary, error) {
+	return a.GetSubUserBeneficiariesMasterWithContext(context.Background(), subUserID)
+}
+
+// GetSubUserBeneficiariesMasterWithContext is like
+// GetSubUserBeneficiariesMaster but binds the request to ctx.
+func (a *API) GetSubUserBeneficiariesMasterWithContext(ctx context.Context,
+	subUserID string) ([]Beneficiary, error) {
+	var out []Beneficiary
+	op := getSubUserBeneficiariesMasterEndpoint.operation(subUserID)
+	if err := a.do(ctx, op, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubUserBeneficiaryMaster returns a single beneficiary of a sub user.
+func (a *API) GetSubUserBeneficiaryMaster(subUserID string, beneficiaryID string) (*BeneficiaryBase, error) {
+	return a.GetSubUserBeneficiaryMasterWithContext(context.Background(),
+		subUserID, beneficiaryID)
+}
+
+// GetSubUserBeneficiaryMasterWithContext is like GetSubUserBeneficiaryMaster
+// but binds the request to ctx.
+func (a *API) GetSubUserBeneficiaryMasterWithContext(ctx context.Context,
+	subUserID string, beneficiaryID string) (*BeneficiaryBase, error) {
+	out := &BeneficiaryBase{}
+	op := getSubUserBeneficiaryMasterEndpoint.operation(subUserID, beneficiaryID)
+	if err := a.do(ctx, op, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateSubUserBeneficiaryMaster creates a beneficiary for a sub user.
+func (a *API) CreateSubUserBeneficiaryMaster(subUserID string, body *BeneficiaryInput) (*BeneficiaryBase, error) {
+	return a.CreateSubUserBeneficiaryMasterWithContext(context.Background(),
+		subUserID, body)
+}
+
+// CreateSubUserBeneficiaryMasterWithContext is like
+// CreateSubUserBeneficiaryMaster but binds the request to ctx.
+func (a *API) CreateSubUserBeneficiaryMasterWithContext(ctx context.Context,
+	subUserID string, body *BeneficiaryInput) (*BeneficiaryBase, error) {
+	out := &BeneficiaryBase{}
+	op := createSubUserBeneficiaryMasterEndpoint.operation(subUserID)
+	if err := a.do(ctx, op, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateSubUserBeneficiaryMaster updates a beneficiary of a sub user.
+func (a *API) UpdateSubUserBeneficiaryMaster(subUserID string, beneficiaryID string,
+	body *UpdateBeneficiaryInput) (*BeneficiaryBase, error) {
+	return a.UpdateSubUserBeneficiaryMasterWithContext(context.Background(),
+		subUserID, beneficiaryID, body)
+}
+
+// UpdateSubUserBeneficiaryMasterWithContext is like
+// UpdateSubUserBeneficiaryMaster but binds the request to ctx.
+func (a *API) UpdateSubUserBeneficiaryMasterWithContext(ctx context.Context,
+	subUserID string, beneficiaryID string,
+	body *UpdateBeneficiaryInput) (*BeneficiaryBase, error) {
+	out := &BeneficiaryBase{}
+	op := updateSubUserBeneficiaryMasterEndpoint.operation(subUserID, beneficiaryID)
+	if err := a.do(ctx, op, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
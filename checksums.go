@@ -0,0 +1,159 @@
+package routefusion
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ibanLengths are the lengths of the IBANs of the countries using them.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AT": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28,
+	"CZ": 24, "DE": 22, "DK": 18, "EE": 20, "ES": 24, "FI": 18, "FR": 27,
+	"GB": 22, "GI": 23, "GR": 27, "HR": 21, "HU": 28, "IE": 22, "IL": 23,
+	"IS": 26, "IT": 27, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27,
+	"MT": 31, "NL": 18, "NO": 15, "PL": 28, "PT": 25, "RO": 24, "SA": 24,
+	"SE": 24, "SI": 19, "SK": 24, "SM": 27, "TR": 26,
+}
+
+var (
+	swiftBicPattern = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	bsbPattern      = regexp.MustCompile(`^[0-9]{6}$`)
+	ibanPattern     = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+)
+
+// usesIBAN reports whether banks of country are identified by IBANs.
+func usesIBAN(country string) bool {
+	_, ok := ibanLengths[strings.ToUpper(country)]
+	return ok
+}
+
+// compact removes the separators people put in account numbers.
+func compact(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "", "/", "").Replace(s)
+}
+
+// digits returns the digits of s, or nil if s has other characters or is
+// not n characters long.
+func digits(s string, n int) []int {
+	if len(s) != n {
+		return nil
+	}
+	d := make([]int, n)
+	for i, c := range s {
+		if c < '0' || c > '9' {
+			return nil
+		}
+		d[i] = int(c - '0')
+	}
+	return d
+}
+
+// validIBAN reports whether iban is a valid IBAN of country, checking its
+// length and its mod-97 check digits.
+func validIBAN(iban, country string) bool {
+	iban = strings.ToUpper(compact(iban))
+	if !ibanPattern.MatchString(iban) || iban[:2] != strings.ToUpper(country) ||
+		len(iban) != ibanLengths[iban[:2]] {
+		return false
+	}
+
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' {
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder == 1
+}
+
+// validABA reports whether routing is a valid ABA routing number.
+func validABA(routing string) bool {
+	d := digits(compact(routing), 9)
+	if d == nil {
+		return false
+	}
+	sum := 3*(d[0]+d[3]+d[6]) + 7*(d[1]+d[4]+d[7]) + d[2] + d[5] + d[8]
+	return sum%10 == 0
+}
+
+// validCLABE reports whether clabe is a valid Mexican CLABE.
+func validCLABE(clabe string) bool {
+	d := digits(compact(clabe), 18)
+	if d == nil {
+		return false
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += d[i] * weights[i%3] % 10
+	}
+	return (10-sum%10)%10 == d[17]
+}
+
+// validCPFOrCNPJ reports whether s is a valid Brazilian CPF or CNPJ.
+func validCPFOrCNPJ(s string) bool {
+	s = compact(s)
+	if len(s) == 11 {
+		return validCPF(digits(s, 11))
+	}
+	return validCNPJ(digits(s, 14))
+}
+
+func validCPF(d []int) bool {
+	if d == nil || allSame(d) {
+		return false
+	}
+	for n := 9; n <= 10; n++ {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += d[i] * (n + 1 - i)
+		}
+		if sum*10%11%10 != d[n] {
+			return false
+		}
+	}
+	return true
+}
+
+func validCNPJ(d []int) bool {
+	if d == nil || allSame(d) {
+		return false
+	}
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	for n := 12; n <= 13; n++ {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += d[i] * weights[i+13-n]
+		}
+		check := 11 - sum%11
+		if check >= 10 {
+			check = 0
+		}
+		if check != d[n] {
+			return false
+		}
+	}
+	return true
+}
+
+func allSame(d []int) bool {
+	for _, v := range d[1:] {
+		if v != d[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// validBSB reports whether bsb is an Australian BSB number, ignoring
+// separators as in "062-000" or "062 000".
+func validBSB(bsb string) bool {
+	return bsbPattern.MatchString(compact(strings.TrimSpace(bsb)))
+}
+
+// validSwiftBic reports whether bic is formatted as a SWIFT/BIC code.
+func validSwiftBic(bic string) bool {
+	return swiftBicPattern.MatchString(strings.ToUpper(strings.TrimSpace(bic)))
+}
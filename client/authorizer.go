@@ -29,3 +29,11 @@ func (b *BearerTokenAuthorizer) AuthorizeRequest(r *http.Request) {
 		r.Header.Set(bearerTokenAuthorization, fmt.Sprintf("Bearer %s", b.Token))
 	}
 }
+
+// AuthorizeHandler is the Sign handler authorizing every attempt with the
+// Authorizer of the request.
+var AuthorizeHandler = NamedHandler{Name: "client.Authorize", Fn: func(r *Request) {
+	if r.Authorizer != nil {
+		r.Authorizer.AuthorizeRequest(r.HTTPRequest)
+	}
+}}
@@ -1,7 +1,7 @@
 package client
 
 import (
-	"io"
+	"context"
 	"net/http"
 	"time"
 )
@@ -25,6 +25,14 @@ var (
 type Client struct {
 	Authorizer Authorizer
 	Retryer    Retryer
+	Logger     Logger
+	LogBodies  bool
+	Tracer     Tracer
+	Metrics    Metrics
+
+	// Handlers are copied into every request of the client. Handlers can be
+	// added or removed before requests are made, not while they are.
+	Handlers Handlers
 
 	httpClient *http.Client
 	baseURL    string
@@ -45,6 +53,11 @@ func NewClient(config Config) *Client {
 		httpClient: httpClient,
 		Retryer:    config.Retryer,
 		Authorizer: config.Authorizer,
+		Logger:     config.Logger,
+		LogBodies:  config.LogBodies,
+		Tracer:     config.Tracer,
+		Metrics:    config.Metrics,
+		Handlers:   DefaultHandlers(),
 	}
 
 	return client
@@ -56,6 +69,12 @@ func sanitize(config Config) Config {
 	sanitized := Config{
 		Authorizer: config.Authorizer,
 		BaseURL:    config.BaseURL,
+		Logger:     config.Logger,
+		LogBodies:  config.LogBodies,
+		Tracer:     config.Tracer,
+		Metrics:    config.Metrics,
+		HTTPClient: config.HTTPClient,
+		Transport:  config.Transport,
 	}
 	sanitized.Retryer = config.Retryer
 	if config.Retryer == nil {
@@ -85,31 +104,66 @@ func sanitize(config Config) Config {
 	return sanitized
 }
 
-// newHTTPClient creates a HTTP client based on go's http.DefaultClient and
-// http.DefaultTransport.
+// newHTTPClient creates a HTTP client owned by a single Client. Unless a
+// transport is injected through the config, it uses a clone of
+// http.DefaultTransport so that the process wide defaults are left untouched.
 func newHTTPClient(config Config) *http.Client {
-	defaultTransport, _ := http.DefaultTransport.(*http.Transport)
+	if config.HTTPClient != nil {
+		return config.HTTPClient
+	}
 
-	defaultTransport.TLSHandshakeTimeout = *config.TLSHandshakeTimeout
-	defaultTransport.MaxIdleConns = *config.MaxIdleConns
-	defaultTransport.MaxIdleConnsPerHost = *config.MaxIdleConnsPerHost
-	defaultTransport.IdleConnTimeout = *config.IdleConnTimeout
+	transport := config.Transport
+	if transport == nil {
+		transport = newTransport(config)
+	}
 
-	client := http.DefaultClient
-	client.Transport = defaultTransport
-	client.Timeout = *config.RequestTimeout
-	return client
+	return &http.Client{
+		Transport: transport,
+		Timeout:   *config.RequestTimeout,
+	}
+}
+
+// newTransport clones http.DefaultTransport and applies the fine-tuning
+// values of the config to the clone.
+func newTransport(config Config) *http.Transport {
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		defaultTransport = &http.Transport{}
+	}
+
+	transport := defaultTransport.Clone()
+	transport.TLSHandshakeTimeout = *config.TLSHandshakeTimeout
+	transport.MaxIdleConns = *config.MaxIdleConns
+	transport.MaxIdleConnsPerHost = *config.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = *config.IdleConnTimeout
+	return transport
 }
 
-// NewRequest is to get a new Request option tied to a client
+// NewRequest is to get a new Request option tied to a client. See the package
+// level NewRequest for the accepted body values.
 func (c *Client) NewRequest(op Operation, output interface{},
-	body io.ReadSeeker, paramsList ...map[string]string) (*Request, error) {
+	body interface{}, paramsList ...map[string]string) (*Request, error) {
+	return c.NewRequestWithContext(context.Background(), op, output, body,
+		paramsList...)
+}
+
+// NewRequestWithContext is like NewRequest but binds the request to ctx.
+func (c *Client) NewRequestWithContext(ctx context.Context, op Operation,
+	output interface{}, body interface{},
+	paramsList ...map[string]string) (*Request, error) {
 	var params map[string]string
-	if len(paramsList) == 0 {
-		params = nil
-	} else {
+	if len(paramsList) != 0 {
 		params = paramsList[0]
 	}
-	return NewRequest(c.httpClient, c.Retryer, c.Authorizer, c.baseURL, op,
-		output, body, params)
+	req, err := NewRequestWithContext(ctx, c.httpClient, c.Retryer, c.Authorizer,
+		c.baseURL, op, output, body, params)
+	if err != nil {
+		return nil, err
+	}
+	req.Logger = c.Logger
+	req.LogBodies = c.LogBodies
+	req.Tracer = c.Tracer
+	req.Metrics = c.Metrics
+	req.Handlers = c.Handlers.Copy()
+	return req, nil
 }
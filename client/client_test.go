@@ -84,3 +84,72 @@ func TestSanitizeConfig(t *testing.T) {
 		})
 	}
 }
+
+type mockRoundTripper struct {
+	called bool
+}
+
+func (m *mockRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	m.called = true
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestNewHTTPClientLeavesDefaultsUntouched(t *testing.T) {
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	expectedTransport := defaultTransport.Clone()
+	expectedClient := *http.DefaultClient
+
+	c1 := newHTTPClient(sanitize(Config{
+		RequestTimeout:      Duration(2 * time.Second),
+		TLSHandshakeTimeout: Duration(3 * time.Second),
+		MaxIdleConns:        Int(42),
+		MaxIdleConnsPerHost: Int(73),
+		IdleConnTimeout:     Duration(4 * time.Second),
+	}))
+	c2 := newHTTPClient(sanitize(Config{}))
+
+	assert.Equal(t, expectedTransport.TLSHandshakeTimeout, defaultTransport.TLSHandshakeTimeout)
+	assert.Equal(t, expectedTransport.MaxIdleConns, defaultTransport.MaxIdleConns)
+	assert.Equal(t, expectedTransport.MaxIdleConnsPerHost, defaultTransport.MaxIdleConnsPerHost)
+	assert.Equal(t, expectedTransport.IdleConnTimeout, defaultTransport.IdleConnTimeout)
+	assert.Equal(t, expectedClient.Timeout, http.DefaultClient.Timeout)
+	assert.Equal(t, expectedClient.Transport, http.DefaultClient.Transport)
+
+	assert.NotSame(t, http.DefaultClient, c1)
+	assert.NotSame(t, c1, c2)
+	assert.NotSame(t, c1.Transport, c2.Transport)
+
+	t1 := c1.Transport.(*http.Transport)
+	assert.Equal(t, 2*time.Second, c1.Timeout)
+	assert.Equal(t, 3*time.Second, t1.TLSHandshakeTimeout)
+	assert.Equal(t, 42, t1.MaxIdleConns)
+	assert.Equal(t, 73, t1.MaxIdleConnsPerHost)
+	assert.Equal(t, 4*time.Second, t1.IdleConnTimeout)
+
+	t2 := c2.Transport.(*http.Transport)
+	assert.Equal(t, defaultRequestTimeout, c2.Timeout)
+	assert.Equal(t, defaultMaxIdleConnsPerHost, t2.MaxIdleConnsPerHost)
+}
+
+func TestNewHTTPClientInjection(t *testing.T) {
+	t.Run("injected http client is used as is", func(t *testing.T) {
+		hc := &http.Client{}
+		c := NewClient(Config{HTTPClient: hc})
+		assert.Same(t, hc, c.httpClient)
+	})
+
+	t.Run("injected transport is used for requests", func(t *testing.T) {
+		rt := &mockRoundTripper{}
+		c := NewClient(Config{BaseURL: "http://base.url", Transport: rt})
+		assert.Same(t, rt, c.httpClient.Transport)
+
+		req, err := c.NewRequest(Operation{HTTPMethod: "GET", HTTPPath: "/"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := req.Send(); err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, rt.called)
+	})
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"net/http"
 	"time"
 )
 
@@ -10,6 +11,33 @@ type Config struct {
 	Authorizer Authorizer
 	BaseURL    string
 
+	// Logger, when set, receives a log entry for every attempt of every
+	// request.
+	Logger Logger
+
+	// LogBodies makes Logger also receive the headers and bodies of requests
+	// and responses at LogDebug, with credentials, account numbers, tax IDs
+	// and KYC ID numbers redacted.
+	LogBodies bool
+
+	// Tracer, when set, traces every request with a span per operation and
+	// a span per attempt, and propagates the trace on outgoing requests.
+	Tracer Tracer
+
+	// Metrics, when set, receives the counters, histograms and gauges of
+	// every request: requests by operation and status, retries and their
+	// delays, error codes and requests in flight.
+	Metrics Metrics
+
+	// HTTPClient, when set, is used as is to make the requests and all the
+	// fields below are ignored.
+	HTTPClient *http.Client
+
+	// Transport, when set, is used as the transport of the HTTP client
+	// instead of a clone of http.DefaultTransport. The transport fine-tuning
+	// fields below are not applied to it.
+	Transport http.RoundTripper
+
 	// used to fine-tune the underlying transport of the HTTP client.
 	RequestTimeout      *time.Duration
 	TLSHandshakeTimeout *time.Duration
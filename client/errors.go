@@ -1,7 +1,11 @@
 package client
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type ErrorCode string
@@ -11,6 +15,10 @@ const (
 	ErrCodeNotFound        = "not_found"
 	ErrCodeUnmarshalFailed = "unmarshal_failed"
 
+	// ErrCodeRequestCanceled is for requests whose context was cancelled or
+	// whose context deadline passed.
+	ErrCodeRequestCanceled = "request_canceled"
+
 	// ErrCodeUndefined is for generic unknown/unexpected errors.
 	ErrCodeUndefined = "unknown"
 )
@@ -47,10 +55,63 @@ type RequestFailureError interface {
 	RequestID() string
 }
 
+// IdempotentRequestError is a RequestFailureError that also tells the
+// idempotency key the request was sent with, see IdempotencyKeyFromError.
+type IdempotentRequestError interface {
+	RequestFailureError
+
+	// The idempotency key the request was sent with. This will be empty if
+	// the request was sent without one.
+	IdempotencyKey() string
+}
+
+// IdempotencyKeyFromError returns the idempotency key of the request that
+// failed with err, if any, so that it can be retried with the same key.
+func IdempotencyKeyFromError(err error) string {
+	var ierr IdempotentRequestError
+	if errors.As(err, &ierr) {
+		return ierr.IdempotencyKey()
+	}
+	return ""
+}
+
 func NewRequestFailureError(err RFError, statusCode int, reqID string) RequestFailureError {
 	return newRequestError(err, statusCode, reqID)
 }
 
+// ServiceError is a RequestFailureError that carries the error payload
+// returned by the Routefusion API.
+type ServiceError interface {
+	RequestFailureError
+
+	// Returns the error code returned by the API. This will be empty if the
+	// response did not contain one.
+	APICode() string
+
+	// Returns the error message returned by the API. This will be empty if
+	// the response did not contain one.
+	APIMessage() string
+
+	// Returns the validation failures of single request fields, if any.
+	FieldErrors() []FieldError
+
+	// Returns the raw body of the failed response.
+	Body() []byte
+}
+
+// FieldError is the validation failure of a single request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// NewServiceError decodes the error payload in body and returns it wrapped
+// around err. Payloads that cannot be decoded are kept available through
+// Body().
+func NewServiceError(err RFError, statusCode int, reqID string, body []byte) ServiceError {
+	return newServiceError(err, statusCode, reqID, body)
+}
+
 func printError(code, message, extra string, origErr error) string {
 	msg := fmt.Sprintf("%s: %s", code, message)
 	if extra != "" {
@@ -97,10 +158,16 @@ func (b baseError) OrigErr() error {
 	return b.origErr
 }
 
+// Unwrap returns the original error to support errors.Is and errors.As.
+func (b baseError) Unwrap() error {
+	return b.origErr
+}
+
 type requestError struct {
 	RFError
-	statusCode int
-	requestID  string
+	statusCode     int
+	requestID      string
+	idempotencyKey string
 }
 
 func newRequestError(err RFError, statusCode int, requestID string) *requestError {
@@ -129,6 +196,152 @@ func (r requestError) RequestID() string {
 	return r.requestID
 }
 
+func (r requestError) IdempotencyKey() string {
+	return r.idempotencyKey
+}
+
 func (r requestError) OrigErrs() []error {
 	return []error{r.OrigErr()}
 }
+
+// Unwrap returns the original error to support errors.Is and errors.As.
+func (r requestError) Unwrap() error {
+	return r.OrigErr()
+}
+
+type serviceError struct {
+	*requestError
+	apiCode     string
+	apiMessage  string
+	fieldErrors []FieldError
+	body        []byte
+}
+
+func newServiceError(err RFError, statusCode int, reqID string, body []byte) *serviceError {
+	s := &serviceError{
+		requestError: newRequestError(err, statusCode, reqID),
+		body:         body,
+	}
+
+	var payload errorPayload
+	if json.Unmarshal(body, &payload) == nil {
+		s.apiCode = firstNonEmpty(rawString(payload.Code),
+			rawString(payload.ErrorCode))
+		s.apiMessage = firstNonEmpty(payload.Message, payload.ErrorMessage,
+			rawString(payload.Error))
+		s.fieldErrors = decodeFieldErrors(payload.Errors)
+	}
+	return s
+}
+
+func (s serviceError) Error() string {
+	extra := fmt.Sprintf("status code: %d, request id: %s",
+		s.statusCode, s.requestID)
+	if s.apiCode != "" || s.apiMessage != "" {
+		extra = fmt.Sprintf("%s\n\tapi error: %s: %s", extra, s.apiCode,
+			s.apiMessage)
+	}
+	for _, f := range s.fieldErrors {
+		extra = fmt.Sprintf("%s\n\t%s: %s", extra, f.Field, f.Message)
+	}
+	return printError(s.Code(), s.Message(), extra, s.OrigErr())
+}
+
+func (s serviceError) String() string {
+	return s.Error()
+}
+
+func (s serviceError) APICode() string {
+	return s.apiCode
+}
+
+func (s serviceError) APIMessage() string {
+	return s.apiMessage
+}
+
+func (s serviceError) FieldErrors() []FieldError {
+	return s.fieldErrors
+}
+
+func (s serviceError) Body() []byte {
+	return s.body
+}
+
+// errorPayload lists the fields Routefusion is known to describe failures
+// with. Codes and errors come as strings, numbers or objects depending on the
+// endpoint, hence the raw messages.
+type errorPayload struct {
+	Code         json.RawMessage `json:"code"`
+	ErrorCode    json.RawMessage `json:"error_code"`
+	Message      string          `json:"message"`
+	ErrorMessage string          `json:"error_message"`
+	Error        json.RawMessage `json:"error"`
+	Errors       json.RawMessage `json:"errors"`
+}
+
+// rawString returns JSON strings unquoted and numbers as they are. Any other
+// value results in an empty string.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// decodeFieldErrors accepts a list of field errors, a list of messages or an
+// object keyed by field holding one or many messages.
+func decodeFieldErrors(raw json.RawMessage) []FieldError {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var list []FieldError
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+
+	list = nil
+	var messages []string
+	if json.Unmarshal(raw, &messages) == nil {
+		for _, m := range messages {
+			list = append(list, FieldError{Message: m})
+		}
+		return list
+	}
+
+	var byField map[string]json.RawMessage
+	if json.Unmarshal(raw, &byField) != nil {
+		return nil
+	}
+	fields := make([]string, 0, len(byField))
+	for field := range byField {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		var fieldMessages []string
+		if json.Unmarshal(byField[field], &fieldMessages) != nil {
+			fieldMessages = []string{rawString(byField[field])}
+		}
+		list = append(list, FieldError{
+			Field:   field,
+			Message: strings.Join(fieldMessages, "; "),
+		})
+	}
+	return list
+}
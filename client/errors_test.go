@@ -55,3 +55,87 @@ func Test_PrintError(t *testing.T) {
 	}
 
 }
+
+func Test_NewServiceError(t *testing.T) {
+	var tests = []struct {
+		desc                string
+		body                string
+		expectedAPICode     string
+		expectedAPIMessage  string
+		expectedFieldErrors []FieldError
+	}{
+		{
+			desc:               "error code and message",
+			body:               `{"error_code": 400, "error_message": "bad request"}`,
+			expectedAPICode:    "400",
+			expectedAPIMessage: "bad request",
+		},
+		{
+			desc:               "code, message and a list of field errors",
+			body:               `{"code": "invalid_input", "message": "validation failed", "errors": [{"field": "currency", "message": "is required"}]}`,
+			expectedAPICode:    "invalid_input",
+			expectedAPIMessage: "validation failed",
+			expectedFieldErrors: []FieldError{
+				{Field: "currency", Message: "is required"},
+			},
+		},
+		{
+			desc:               "errors keyed by field",
+			body:               `{"error": "validation failed", "errors": {"swift_bic": ["is invalid", "is too short"], "account_number": "is required"}}`,
+			expectedAPIMessage: "validation failed",
+			expectedFieldErrors: []FieldError{
+				{Field: "account_number", Message: "is required"},
+				{Field: "swift_bic", Message: "is invalid; is too short"},
+			},
+		},
+		{
+			desc: "list of messages",
+			body: `{"errors": ["quote expired"]}`,
+			expectedFieldErrors: []FieldError{
+				{Message: "quote expired"},
+			},
+		},
+		{
+			desc: "body that is not json",
+			body: `<html>bad gateway</html>`,
+		},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			err := NewServiceError(NewRFError(ErrCodeUndefined, "failed", nil),
+				400, "req-1", []byte(testcase.body))
+			assert.Equal(testcase.expectedAPICode, err.APICode())
+			assert.Equal(testcase.expectedAPIMessage, err.APIMessage())
+			assert.Equal(testcase.expectedFieldErrors, err.FieldErrors())
+			assert.Equal(testcase.body, string(err.Body()))
+			assert.Equal(400, err.StatusCode())
+			assert.Equal("req-1", err.RequestID())
+		})
+	}
+}
+
+func Test_ServiceErrorUnwrap(t *testing.T) {
+	origErr := errors.New("Mayday mayday mayday")
+	var err error = NewServiceError(NewRFError(ErrCodeUndefined, "failed", origErr),
+		500, "req-1", nil)
+
+	assert.True(t, errors.Is(err, origErr))
+
+	var rerr RequestFailureError
+	if assert.True(t, errors.As(err, &rerr)) {
+		assert.Equal(t, "req-1", rerr.RequestID())
+	}
+
+	var serr ServiceError
+	assert.True(t, errors.As(err, &serr))
+}
+
+func Test_ServiceErrorMessage(t *testing.T) {
+	err := NewServiceError(NewRFError(ErrCodeUndefined, "failed", nil), 422,
+		"req-1", []byte(`{"code": "invalid", "message": "validation failed", "errors": [{"field": "currency", "message": "is required"}]}`))
+	assert.Equal(t, "unknown: failed\n\tstatus code: 422, request id: req-1"+
+		"\n\tapi error: invalid: validation failed\n\tcurrency: is required",
+		err.Error())
+}
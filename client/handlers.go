@@ -0,0 +1,151 @@
+package client
+
+// A NamedHandler is a handler of a phase of a request, named so that it can
+// be found, replaced or removed.
+type NamedHandler struct {
+	Name string
+	Fn   func(*Request)
+}
+
+// HandlerList is an ordered list of handlers. Every handler of the list
+// runs, even after one of them sets Request.Error, so handlers that only make
+// sense on success check it first.
+type HandlerList struct {
+	list []NamedHandler
+}
+
+// Len returns the number of handlers of the list.
+func (l *HandlerList) Len() int {
+	return len(l.list)
+}
+
+// Names returns the names of the handlers, in the order they run.
+func (l *HandlerList) Names() []string {
+	names := make([]string, len(l.list))
+	for i, h := range l.list {
+		names[i] = h.Name
+	}
+	return names
+}
+
+// PushBack adds an unnamed handler at the end of the list.
+func (l *HandlerList) PushBack(fn func(*Request)) {
+	l.PushBackNamed(NamedHandler{Fn: fn})
+}
+
+// PushBackNamed adds h at the end of the list.
+func (l *HandlerList) PushBackNamed(h NamedHandler) {
+	l.list = append(l.list, h)
+}
+
+// PushFront adds an unnamed handler at the start of the list.
+func (l *HandlerList) PushFront(fn func(*Request)) {
+	l.PushFrontNamed(NamedHandler{Fn: fn})
+}
+
+// PushFrontNamed adds h at the start of the list.
+func (l *HandlerList) PushFrontNamed(h NamedHandler) {
+	l.list = append([]NamedHandler{h}, l.list...)
+}
+
+// Remove removes the handlers named like h.
+func (l *HandlerList) Remove(h NamedHandler) {
+	l.RemoveByName(h.Name)
+}
+
+// RemoveByName removes the handlers named name.
+func (l *HandlerList) RemoveByName(name string) {
+	list := l.list[:0:0]
+	for _, h := range l.list {
+		if h.Name != name {
+			list = append(list, h)
+		}
+	}
+	l.list = list
+}
+
+// SwapNamed replaces the handlers named like h by h and reports whether
+// there were any.
+func (l *HandlerList) SwapNamed(h NamedHandler) bool {
+	swapped := false
+	for i := range l.list {
+		if l.list[i].Name == h.Name {
+			l.list[i] = h
+			swapped = true
+		}
+	}
+	return swapped
+}
+
+// Clear removes every handler.
+func (l *HandlerList) Clear() {
+	l.list = nil
+}
+
+// Run runs the handlers on r, in order.
+func (l *HandlerList) Run(r *Request) {
+	for _, h := range l.list {
+		h.Fn(r)
+	}
+}
+
+// copy returns a list that can be changed without changing l.
+func (l HandlerList) copy() HandlerList {
+	return HandlerList{list: append([]NamedHandler(nil), l.list...)}
+}
+
+// Handlers are the phases a request goes through when it is sent:
+//
+//   - Build runs once, before the first attempt.
+//   - Sign, Send and ValidateResponse run on every attempt. Send makes the
+//     HTTP call, setting Request.HTTPResponse or Request.LastErr, and
+//     ValidateResponse turns failures into Request.Error.
+//   - Retry runs after every attempt and sets Request.Retryable and
+//     Request.RetryDelay when the request is to be sent again.
+//   - Unmarshal runs once the last attempt succeeded, to decode the response
+//     into Request.Output.
+//   - Complete runs last, whatever the outcome.
+type Handlers struct {
+	Build            HandlerList
+	Sign             HandlerList
+	Send             HandlerList
+	ValidateResponse HandlerList
+	Retry            HandlerList
+	Unmarshal        HandlerList
+	Complete         HandlerList
+}
+
+// Copy returns handlers that can be changed without changing h.
+func (h Handlers) Copy() Handlers {
+	return Handlers{
+		Build:            h.Build.copy(),
+		Sign:             h.Sign.copy(),
+		Send:             h.Send.copy(),
+		ValidateResponse: h.ValidateResponse.copy(),
+		Retry:            h.Retry.copy(),
+		Unmarshal:        h.Unmarshal.copy(),
+		Complete:         h.Complete.copy(),
+	}
+}
+
+// DefaultHandlers returns the handlers requests are sent with unless they
+// are changed.
+func DefaultHandlers() Handlers {
+	var h Handlers
+	h.Build.PushBackNamed(StartOperationSpanHandler)
+	h.Build.PushBackNamed(StartMetricsHandler)
+	h.Sign.PushBackNamed(StartAttemptSpanHandler)
+	h.Sign.PushBackNamed(AuthorizeHandler)
+	h.Send.PushBackNamed(LogRequestBodyHandler)
+	h.Send.PushBackNamed(SendHandler)
+	h.Send.PushBackNamed(LogResponseBodyHandler)
+	h.ValidateResponse.PushBackNamed(ValidateResponseHandler)
+	h.Retry.PushBackNamed(EndAttemptSpanHandler)
+	h.Retry.PushBackNamed(RetryHandler)
+	h.Retry.PushBackNamed(RetryMetricsHandler)
+	h.Retry.PushBackNamed(LogAttemptHandler)
+	h.Unmarshal.PushBackNamed(UnmarshalHandler)
+	h.Complete.PushBackNamed(CompleteMetricsHandler)
+	h.Complete.PushBackNamed(EndOperationSpanHandler)
+	return h
+}
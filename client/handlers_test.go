@@ -0,0 +1,194 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func namedHandler(name string) NamedHandler {
+	return NamedHandler{Name: name, Fn: func(*Request) {}}
+}
+
+func Test_HandlerList(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		change   func(l *HandlerList)
+		expected []string
+	}{
+		{
+			desc: "push back and front",
+			change: func(l *HandlerList) {
+				l.PushFrontNamed(namedHandler("first"))
+				l.PushBackNamed(namedHandler("last"))
+			},
+			expected: []string{"first", "a", "b", "last"},
+		},
+		{
+			desc: "unnamed",
+			change: func(l *HandlerList) {
+				l.PushBack(func(*Request) {})
+			},
+			expected: []string{"a", "b", ""},
+		},
+		{
+			desc: "remove",
+			change: func(l *HandlerList) {
+				l.Remove(namedHandler("a"))
+				l.RemoveByName("unknown")
+			},
+			expected: []string{"b"},
+		},
+		{
+			desc: "swap",
+			change: func(l *HandlerList) {
+				assert.True(t, l.SwapNamed(namedHandler("b")))
+				assert.False(t, l.SwapNamed(namedHandler("c")))
+			},
+			expected: []string{"a", "b"},
+		},
+		{
+			desc: "clear",
+			change: func(l *HandlerList) {
+				l.Clear()
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			l := HandlerList{}
+			l.PushBackNamed(namedHandler("a"))
+			l.PushBackNamed(namedHandler("b"))
+			original := l.copy()
+
+			testCase.change(&l)
+			assert.Equal(t, testCase.expected, l.Names())
+			assert.Equal(t, len(testCase.expected), l.Len())
+			assert.Equal(t, []string{"a", "b"}, original.Names())
+		})
+	}
+}
+
+func Test_HandlersOrder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer ts.Close()
+
+	var phases []string
+	cl := NewClient(Config{BaseURL: ts.URL})
+	record := func(phase string) NamedHandler {
+		return NamedHandler{Name: "test.Record", Fn: func(*Request) {
+			phases = append(phases, phase)
+		}}
+	}
+	cl.Handlers.Build.PushBackNamed(record("build"))
+	cl.Handlers.Sign.PushBackNamed(record("sign"))
+	cl.Handlers.Send.PushFrontNamed(record("send"))
+	cl.Handlers.ValidateResponse.PushBackNamed(record("validate"))
+	cl.Handlers.Retry.PushBackNamed(record("retry"))
+	cl.Handlers.Unmarshal.PushBackNamed(record("unmarshal"))
+	cl.Handlers.Complete.PushBackNamed(record("complete"))
+
+	req, err := cl.NewRequest(Operation{HTTPMethod: "GET", HTTPPath: "/v1/path"},
+		&basicOutputType{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, req.Send())
+	assert.Equal(t, []string{"build", "sign", "send", "validate", "retry", "unmarshal",
+		"complete"}, phases)
+}
+
+func Test_HandlersCustom(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		change         func(h *Handlers)
+		expectedHeader string
+		expectedAuth   string
+		expectedErr    string
+	}{
+		{
+			desc:         "defaults",
+			change:       func(h *Handlers) {},
+			expectedAuth: "Bearer secret",
+		},
+		{
+			desc: "custom header on every attempt",
+			change: func(h *Handlers) {
+				h.Sign.PushBack(func(r *Request) {
+					r.HTTPRequest.Header.Set("X-Custom", "value")
+				})
+			},
+			expectedHeader: "value",
+			expectedAuth:   "Bearer secret",
+		},
+		{
+			desc: "authorizer removed",
+			change: func(h *Handlers) {
+				h.Sign.Remove(AuthorizeHandler)
+			},
+		},
+		{
+			desc: "build error skips sending",
+			change: func(h *Handlers) {
+				h.Build.PushBack(func(r *Request) {
+					r.Error = NewRequestFailureError(
+						NewRFError(ErrCodeUndefined, "not built", nil), 0, "")
+				})
+			},
+			expectedErr: "not built",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+				r *http.Request) {
+				attempts++
+				assert.Equal(t, testCase.expectedHeader, r.Header.Get("X-Custom"))
+				assert.Equal(t, testCase.expectedAuth, r.Header.Get("Authorization"))
+			}))
+			defer ts.Close()
+
+			cl := NewClient(Config{BaseURL: ts.URL,
+				Authorizer: &BearerTokenAuthorizer{Token: "secret"}})
+			testCase.change(&cl.Handlers)
+			req, err := cl.NewRequest(Operation{HTTPMethod: "GET", HTTPPath: "/v1/path"}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = req.Send()
+			if testCase.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), testCase.expectedErr)
+				}
+				assert.Equal(t, 0, attempts)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, attempts)
+		})
+	}
+}
+
+func Test_HandlersRequestsCopy(t *testing.T) {
+	cl := NewClient(Config{BaseURL: "http://localhost"})
+	req, err := cl.NewRequest(Operation{HTTPMethod: "GET", HTTPPath: "/v1/path"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Handlers.Sign.Clear()
+	assert.Equal(t, []string{"client.StartAttemptSpan", "client.Authorize"},
+		cl.Handlers.Sign.Names())
+	assert.Equal(t, []string{"client.LogRequestBody", "client.Send", "client.LogResponseBody"},
+		cl.Handlers.Send.Names())
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// HeaderKeyIdempotencyKey is the request header carrying the key that lets
+// Routefusion recognise replays of the same logical request.
+const HeaderKeyIdempotencyKey = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying key. Requests of
+// operations requiring an idempotency key created with the returned context
+// send key instead of a generated one. As replaying a key replays the
+// original response, the context should only be used for a single such
+// request.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key carried by ctx, if
+// any.
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// NewIdempotencyKey returns a random (version 4) UUID to be used as an
+// idempotency key.
+func NewIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("error generating idempotency key: %s", err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10],
+		b[10:]), nil
+}
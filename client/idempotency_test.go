@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewIdempotencyKey(t *testing.T) {
+	uuidV4 := regexp.MustCompile(
+		`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	k1, err := NewIdempotencyKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := NewIdempotencyKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Regexp(t, uuidV4, k1)
+	assert.Regexp(t, uuidV4, k2)
+	assert.NotEqual(t, k1, k2)
+}
+
+func Test_IdempotencyKeyFromContext(t *testing.T) {
+	assert.Equal(t, "", IdempotencyKeyFromContext(context.Background()))
+
+	ctx := WithIdempotencyKey(context.Background(), "key-1")
+	assert.Equal(t, "key-1", IdempotencyKeyFromContext(ctx))
+}
+
+func Test_RequestIdempotencyKey(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		ctx         context.Context
+		op          Operation
+		expectedKey string
+		expectedAny bool
+	}{
+		{
+			desc: "no key by default",
+			ctx:  context.Background(),
+			op:   Operation{HTTPMethod: "POST", HTTPPath: "/test/path"},
+		},
+		{
+			desc: "context key ignored when not required",
+			ctx:  WithIdempotencyKey(context.Background(), "key-1"),
+			op:   Operation{HTTPMethod: "GET", HTTPPath: "/test/path"},
+		},
+		{
+			desc:        "key is generated when required",
+			ctx:         context.Background(),
+			op:          Operation{HTTPMethod: "POST", HTTPPath: "/test/path", RequiresIdempotencyKey: true},
+			expectedAny: true,
+		},
+		{
+			desc:        "caller supplied key takes precedence",
+			ctx:         WithIdempotencyKey(context.Background(), "key-1"),
+			op:          Operation{HTTPMethod: "POST", HTTPPath: "/test/path", RequiresIdempotencyKey: true},
+			expectedKey: "key-1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var sentKeys []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+				r *http.Request) {
+				sentKeys = append(sentKeys, r.Header.Get(HeaderKeyIdempotencyKey))
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer ts.Close()
+
+			cl := NewClient(Config{BaseURL: ts.URL,
+				Retryer: &testRetryer{maxRetries: 2}})
+			req, err := cl.NewRequestWithContext(testCase.ctx, testCase.op, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = req.Send()
+			if _, ok := err.(RequestFailureError); !ok {
+				t.Fatalf("expected a RequestFailureError, actual: %v", err)
+			}
+
+			key := req.IdempotencyKey()
+			if testCase.expectedAny {
+				assert.NotEmpty(t, key)
+			} else {
+				assert.Equal(t, testCase.expectedKey, key)
+			}
+			assert.Equal(t, []string{key, key, key}, sentKeys)
+			assert.Equal(t, key, IdempotencyKeyFromError(err))
+		})
+	}
+}
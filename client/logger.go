@@ -0,0 +1,168 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+// Log levels, from the most verbose.
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// Field is a key and value attached to a log entry or a span.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives the log entries of the client. Requests log every attempt
+// at LogInfo when it succeeds, LogWarn when it is retried and LogError when
+// the request fails, with the method, path, attempt, status, latency and
+// retry delay as fields. With Config.LogBodies, the redacted headers and
+// bodies are logged at LogDebug.
+//
+// Implementations must be safe for concurrent use.
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...Field)
+}
+
+// StdLogger is a Logger writing entries at or above Level to a log.Logger,
+// formatted as "LEVEL message key=value ...".
+type StdLogger struct {
+	Logger *log.Logger
+	Level  LogLevel
+}
+
+// NewStdLogger returns a StdLogger writing to l, the standard logger if nil,
+// the entries at or above level.
+func NewStdLogger(l *log.Logger, level LogLevel) *StdLogger {
+	if l == nil {
+		l = log.New(log.Writer(), "", log.LstdFlags)
+	}
+	return &StdLogger{Logger: l, Level: level}
+}
+
+// Log implements Logger.
+func (s *StdLogger) Log(level LogLevel, msg string, fields ...Field) {
+	if level < s.Level {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(level.String())
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for _, f := range fields {
+		value := fmt.Sprint(f.Value)
+		if strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", f.Key, value)
+	}
+	s.Logger.Print(b.String())
+}
+
+// LogRequestBodyHandler is the Send handler logging the redacted headers and
+// body of the request before its first attempt.
+var LogRequestBodyHandler = NamedHandler{Name: "client.LogRequestBody", Fn: func(r *Request) {
+	if r.Logger == nil || !r.LogBodies || r.RetryCount != 0 {
+		return
+	}
+
+	var body []byte
+	if r.body != nil {
+		if _, err := r.body.Seek(0, io.SeekStart); err == nil {
+			body, _ = ioutil.ReadAll(r.body)
+			r.body.Seek(0, io.SeekStart)
+		}
+	}
+	r.Logger.Log(LogDebug, "request",
+		Field{Key: "method", Value: r.HTTPRequest.Method},
+		Field{Key: "path", Value: r.HTTPRequest.URL.Path},
+		Field{Key: "headers", Value: redactHeaders(r.HTTPRequest.Header)},
+		Field{Key: "body", Value: redactBody(
+			r.HTTPRequest.Header.Get(requestHeaderKeyContentType), body)})
+}}
+
+// LogResponseBodyHandler is the Send handler logging the redacted headers and
+// body of the response, which is read and replaced by a copy.
+var LogResponseBodyHandler = NamedHandler{Name: "client.LogResponseBody", Fn: func(r *Request) {
+	if r.Logger == nil || !r.LogBodies || r.HTTPResponse == nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.HTTPResponse.Body)
+	r.HTTPResponse.Body.Close()
+	r.HTTPResponse.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	r.Logger.Log(LogDebug, "response",
+		Field{Key: "status", Value: r.HTTPResponse.StatusCode},
+		Field{Key: "headers", Value: redactHeaders(r.HTTPResponse.Header)},
+		Field{Key: "body", Value: redactBody(
+			r.HTTPResponse.Header.Get(requestHeaderKeyContentType), body)})
+}}
+
+// LogAttemptHandler is the Retry handler logging the outcome of every
+// attempt, and the delay before the next one if it is retried.
+var LogAttemptHandler = NamedHandler{Name: "client.LogAttempt", Fn: func(r *Request) {
+	if r.Logger == nil {
+		return
+	}
+
+	attempt := r.RetryCount + 1
+	if r.Retryable {
+		attempt = r.RetryCount
+	}
+	level, msg := LogInfo, "request succeeded"
+	fields := []Field{
+		{Key: "method", Value: r.HTTPRequest.Method},
+		{Key: "path", Value: r.HTTPRequest.URL.Path},
+		{Key: "attempt", Value: attempt},
+	}
+	if r.HTTPResponse != nil {
+		fields = append(fields, Field{Key: "status", Value: r.HTTPResponse.StatusCode})
+		if id := r.HTTPResponse.Header.Get(responseHeaderKeyRequestID); id != "" {
+			fields = append(fields, Field{Key: "request_id", Value: id})
+		}
+	}
+	fields = append(fields, Field{Key: "latency", Value: r.AttemptLatency})
+	if r.LastErr != nil {
+		fields = append(fields, Field{Key: "error", Value: r.LastErr})
+	}
+
+	switch {
+	case r.Retryable:
+		level, msg = LogWarn, "request attempt failed, retrying"
+		fields = append(fields, Field{Key: "retry_delay", Value: r.RetryDelay})
+	case r.failed():
+		level, msg = LogError, "request failed"
+	}
+	r.Logger.Log(level, msg, fields...)
+}}
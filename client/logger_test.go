@@ -0,0 +1,240 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntry struct {
+	level  LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger keeps the entries it receives.
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Log(level LogLevel, msg string, fields ...Field) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := logEntry{level: level, msg: msg, fields: map[string]interface{}{}}
+	for _, f := range fields {
+		e.fields[f.Key] = f.Value
+	}
+	l.entries = append(l.entries, e)
+}
+
+func Test_LoggerAttempts(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		statuses         []int
+		expectedLevels   []LogLevel
+		expectedStatuses []interface{}
+	}{
+		{
+			desc:             "success",
+			statuses:         []int{http.StatusOK},
+			expectedLevels:   []LogLevel{LogInfo},
+			expectedStatuses: []interface{}{200},
+		},
+		{
+			desc:             "retried",
+			statuses:         []int{http.StatusBadGateway, http.StatusCreated},
+			expectedLevels:   []LogLevel{LogWarn, LogInfo},
+			expectedStatuses: []interface{}{502, 201},
+		},
+		{
+			desc: "failed",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+				http.StatusNotFound},
+			expectedLevels:   []LogLevel{LogWarn, LogWarn, LogError},
+			expectedStatuses: []interface{}{503, 503, 404},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+				r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-1")
+				w.WriteHeader(testCase.statuses[attempts])
+				attempts++
+			}))
+			defer ts.Close()
+
+			logger := &recordingLogger{}
+			cl := NewClient(Config{BaseURL: ts.URL, Logger: logger,
+				Retryer: &testRetryer{maxRetries: 2}})
+			req, err := cl.NewRequest(Operation{HTTPMethod: "GET", HTTPPath: "/v1/path"}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Send()
+
+			var levels []LogLevel
+			var statuses []interface{}
+			for i, e := range logger.entries {
+				levels = append(levels, e.level)
+				statuses = append(statuses, e.fields["status"])
+				assert.Equal(t, "GET", e.fields["method"])
+				assert.Equal(t, "/v1/path", e.fields["path"])
+				assert.Equal(t, i+1, e.fields["attempt"])
+				assert.Equal(t, "req-1", e.fields["request_id"])
+				assert.IsType(t, time.Duration(0), e.fields["latency"])
+				if e.level == LogWarn {
+					assert.Equal(t, time.Millisecond, e.fields["retry_delay"])
+				} else {
+					assert.NotContains(t, e.fields, "retry_delay")
+				}
+			}
+			assert.Equal(t, testCase.expectedLevels, levels)
+			assert.Equal(t, testCase.expectedStatuses, statuses)
+		})
+	}
+}
+
+func Test_LoggerTransportError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close()
+
+	logger := &recordingLogger{}
+	cl := NewClient(Config{BaseURL: ts.URL, Logger: logger,
+		Retryer: &testRetryer{maxRetries: 0}})
+	req, _ := cl.NewRequest(Operation{HTTPMethod: "GET", HTTPPath: "/v1/path"}, nil, nil)
+	assert.Error(t, req.Send())
+
+	if assert.Len(t, logger.entries, 1) {
+		assert.Equal(t, LogError, logger.entries[0].level)
+		assert.NotContains(t, logger.entries[0].fields, "status")
+		assert.Error(t, logger.entries[0].fields["error"].(error))
+	}
+}
+
+func Test_LoggerBodies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 42, "account_number": "123456", "created": "yes"}`))
+	}))
+	defer ts.Close()
+
+	logger := &recordingLogger{}
+	cl := NewClient(Config{BaseURL: ts.URL, Logger: logger, LogBodies: true,
+		Authorizer: &BearerTokenAuthorizer{Token: "secret-token"}})
+	out := &basicOutputType{}
+	req, err := cl.NewRequest(Operation{HTTPMethod: "POST", HTTPPath: "/v1/path"}, out,
+		map[string]interface{}{"owners": []map[string]string{{"idNumber": "X1"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := req.Send(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 42, out.ID)
+
+	if assert.Len(t, logger.entries, 3) {
+		request, response := logger.entries[0], logger.entries[1]
+		assert.Equal(t, LogDebug, request.level)
+		assert.Equal(t, "Authorization: [REDACTED]\nContent-Type: application/json",
+			request.fields["headers"])
+		assert.Equal(t, `{"owners":[{"idNumber":"[REDACTED]"}]}`, request.fields["body"])
+
+		assert.Equal(t, LogDebug, response.level)
+		assert.Equal(t, 200, response.fields["status"])
+		assert.Equal(t, `{"account_number":"[REDACTED]","created":"yes","id":42}`,
+			response.fields["body"])
+		assert.Equal(t, LogInfo, logger.entries[2].level)
+	}
+}
+
+func Test_LoggerRequestBodyFromStart(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	body := strings.NewReader(`{"iban": "DE89"}`)
+	logger := &recordingLogger{}
+	cl := NewClient(Config{BaseURL: ts.URL, Logger: logger, LogBodies: true})
+	req, err := cl.NewRequest(Operation{HTTPMethod: "POST", HTTPPath: "/v1/path"}, nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Seek(4, io.SeekStart)
+	if err := req.Send(); err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.NotEmpty(t, logger.entries) {
+		assert.Equal(t, `{"iban":"[REDACTED]"}`, logger.entries[0].fields["body"])
+	}
+}
+
+func Test_RedactBody(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{
+			desc:     "nested keys",
+			body:     `{"beneficiary": {"clabe": "0321", "tax_number": 7, "name": "Ana"}, "cpfcnpj": null}`,
+			expected: `{"beneficiary":{"clabe":"[REDACTED]","name":"Ana","tax_number":"[REDACTED]"},"cpfcnpj":null}`,
+		},
+		{
+			desc:     "arrays",
+			body:     `[{"IBAN": "DE89"}, {"amount": 10.50}]`,
+			expected: `[{"IBAN":"[REDACTED]"},{"amount":10.50}]`,
+		},
+		{
+			desc:        "multipart",
+			contentType: "multipart/form-data; boundary=x",
+			body:        "--x\r\n",
+			expected:    "<multipart body of 5 bytes>",
+		},
+		{
+			desc:     "text",
+			body:     "beneficiary_id,amount",
+			expected: "<body of 21 bytes>",
+		},
+		{
+			desc:     "json prefix",
+			body:     "1,4111111111111111,USD",
+			expected: "<body of 22 bytes>",
+		},
+		{
+			desc:     "empty",
+			expected: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			assert.Equal(t, testCase.expected,
+				redactBody(testCase.contentType, []byte(testCase.body)))
+		})
+	}
+}
+
+func Test_StdLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewStdLogger(log.New(buf, "", 0), LogInfo)
+
+	logger.Log(LogDebug, "hidden")
+	logger.Log(LogWarn, "request attempt failed, retrying", Field{Key: "status", Value: 502},
+		Field{Key: "error", Value: "connection reset"}, Field{Key: "retry_delay", Value: time.Second})
+	assert.Equal(t, "WARN request attempt failed, retrying status=502 "+
+		"error=\"connection reset\" retry_delay=1s\n", buf.String())
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"strconv"
+	"time"
+)
+
+// Metrics reported by requests, see Metrics.
+const (
+	// MetricRequests counts the requests by operation and status, the status
+	// code of the last response or "none" when there was none.
+	MetricRequests = "routefusion_requests_total"
+
+	// MetricRequestDuration observes the seconds requests took, retries
+	// included, by operation.
+	MetricRequestDuration = "routefusion_request_duration_seconds"
+
+	// MetricRetries counts the retried attempts by operation.
+	MetricRetries = "routefusion_retries_total"
+
+	// MetricRetryDelay observes the seconds waited before retrying, by
+	// operation.
+	MetricRetryDelay = "routefusion_retry_delay_seconds"
+
+	// MetricErrors counts the failed requests by operation and error code,
+	// e.g. ErrCodeTimeout or ErrCodeNotFound.
+	MetricErrors = "routefusion_errors_total"
+
+	// MetricInFlightRequests is the number of requests being sent, by
+	// operation.
+	MetricInFlightRequests = "routefusion_in_flight_requests"
+)
+
+// Label is the name and value of a dimension of a metric.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Metrics receives the counters, histograms and gauges of the client, named
+// after the Metric constants. PrometheusMetrics exposes them to Prometheus.
+//
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// AddCounter adds value to the counter name.
+	AddCounter(name string, value float64, labels ...Label)
+
+	// ObserveHistogram adds an observation of value to the histogram name.
+	ObserveHistogram(name string, value float64, labels ...Label)
+
+	// AddGauge adds value, which can be negative, to the gauge name.
+	AddGauge(name string, value float64, labels ...Label)
+}
+
+// StartMetricsHandler is the Build handler counting the request in flight.
+var StartMetricsHandler = NamedHandler{Name: "client.StartMetrics", Fn: func(r *Request) {
+	if r.Metrics == nil {
+		return
+	}
+
+	r.metricsStart = time.Now()
+	r.Metrics.AddGauge(MetricInFlightRequests, 1, r.operationLabel())
+}}
+
+// RetryMetricsHandler is the Retry handler counting retried attempts and
+// their delays. It runs after RetryHandler.
+var RetryMetricsHandler = NamedHandler{Name: "client.RetryMetrics", Fn: func(r *Request) {
+	if r.Metrics == nil || !r.Retryable {
+		return
+	}
+
+	r.Metrics.AddCounter(MetricRetries, 1, r.operationLabel())
+	r.Metrics.ObserveHistogram(MetricRetryDelay, r.RetryDelay.Seconds(), r.operationLabel())
+}}
+
+// CompleteMetricsHandler is the Complete handler counting the request by
+// status and error code, observing its duration and taking it out of the
+// requests in flight.
+var CompleteMetricsHandler = NamedHandler{Name: "client.CompleteMetrics", Fn: func(r *Request) {
+	if r.Metrics == nil || r.metricsStart.IsZero() {
+		return
+	}
+
+	status := "none"
+	if r.HTTPResponse != nil {
+		status = strconv.Itoa(r.HTTPResponse.StatusCode)
+	}
+	r.Metrics.AddGauge(MetricInFlightRequests, -1, r.operationLabel())
+	r.Metrics.AddCounter(MetricRequests, 1, r.operationLabel(),
+		Label{Name: "status", Value: status})
+	r.Metrics.ObserveHistogram(MetricRequestDuration, time.Since(r.metricsStart).Seconds(),
+		r.operationLabel())
+	if r.Error != nil {
+		r.Metrics.AddCounter(MetricErrors, 1, r.operationLabel(),
+			Label{Name: "code", Value: r.Error.Code()})
+	}
+	r.metricsStart = time.Time{}
+}}
+
+func (r *Request) operationLabel() Label {
+	return Label{Name: "operation", Value: r.Operation.name()}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Metrics(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		statuses []int
+		expected []string
+	}{
+		{
+			desc:     "retried",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			expected: []string{
+				`routefusion_in_flight_requests{operation="GetThing"} 0`,
+				`routefusion_request_duration_seconds_count{operation="GetThing"} 1`,
+				`routefusion_requests_total{operation="GetThing",status="200"} 1`,
+				`routefusion_retries_total{operation="GetThing"} 1`,
+				`routefusion_retry_delay_seconds_bucket{operation="GetThing",le="0.005"} 1`,
+				`routefusion_retry_delay_seconds_sum{operation="GetThing"} 0.001`,
+			},
+		},
+		{
+			desc:     "not found",
+			statuses: []int{http.StatusNotFound},
+			expected: []string{
+				`routefusion_errors_total{code="not_found",operation="GetThing"} 1`,
+				`routefusion_requests_total{operation="GetThing",status="404"} 1`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var attempts int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+				r *http.Request) {
+				w.WriteHeader(testCase.statuses[atomic.AddInt32(&attempts, 1)-1])
+			}))
+			defer ts.Close()
+
+			metrics := &PrometheusMetrics{}
+			cl := NewClient(Config{BaseURL: ts.URL, Metrics: metrics,
+				Retryer: &testRetryer{maxRetries: 1}})
+			req, err := cl.NewRequest(Operation{Name: "GetThing", HTTPMethod: "GET",
+				HTTPPath: "/v1/things/1"}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Send()
+
+			text := string(metrics.text())
+			for _, line := range testCase.expected {
+				assert.Contains(t, text, line+"\n")
+			}
+		})
+	}
+}
+
+func Test_PrometheusMetrics(t *testing.T) {
+	metrics := &PrometheusMetrics{Buckets: []float64{0.1, 1}}
+	metrics.AddCounter(MetricErrors, 1, Label{Name: "operation", Value: "GetUser"},
+		Label{Name: "code", Value: ErrCodeTimeout})
+	metrics.AddCounter(MetricErrors, 2, Label{Name: "code", Value: ErrCodeTimeout},
+		Label{Name: "operation", Value: "GetUser"})
+	metrics.AddGauge(MetricErrors, 5)
+	metrics.AddGauge("custom_gauge", 3, Label{Name: "name", Value: "a \"quoted\"\nvalue"})
+	metrics.AddGauge("custom_gauge", -1, Label{Name: "name", Value: "a \"quoted\"\nvalue"})
+	metrics.ObserveHistogram(MetricRequestDuration, 0.05, Label{Name: "operation", Value: "GetUser"})
+	metrics.ObserveHistogram(MetricRequestDuration, 0.5, Label{Name: "operation", Value: "GetUser"})
+	metrics.ObserveHistogram(MetricRequestDuration, 2, Label{Name: "operation", Value: "GetUser"})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := ioutil.ReadAll(rec.Body)
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# TYPE custom_gauge gauge
+custom_gauge{name="a \"quoted\"\nvalue"} 2
+# HELP routefusion_errors_total Failed Routefusion requests by operation and error code.
+# TYPE routefusion_errors_total counter
+routefusion_errors_total{code="timeout",operation="GetUser"} 3
+# HELP routefusion_request_duration_seconds Duration of Routefusion requests in seconds, retries included.
+# TYPE routefusion_request_duration_seconds histogram
+routefusion_request_duration_seconds_bucket{operation="GetUser",le="0.1"} 1
+routefusion_request_duration_seconds_bucket{operation="GetUser",le="1"} 2
+routefusion_request_duration_seconds_bucket{operation="GetUser",le="+Inf"} 3
+routefusion_request_duration_seconds_sum{operation="GetUser"} 2.55
+routefusion_request_duration_seconds_count{operation="GetUser"} 3
+`, string(body))
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// errMultipartClosed is returned when writing to a Multipart already sent.
+var errMultipartClosed = errors.New("multipart body already sent")
+
+// Multipart is a multipart/form-data request body. Parts are encoded in
+// memory as they are written, so that every attempt of a retried request
+// sends the same bytes. It is closed when the request is built and cannot
+// be written to afterwards.
+type Multipart struct {
+	buf    bytes.Buffer
+	w      *multipart.Writer
+	closed bool
+}
+
+// NewMultipart returns an empty multipart body.
+func NewMultipart() *Multipart {
+	m := &Multipart{}
+	m.w = multipart.NewWriter(&m.buf)
+	return m
+}
+
+// WriteField adds a form field.
+func (m *Multipart) WriteField(name, value string) error {
+	if m.closed {
+		return errMultipartClosed
+	}
+	return m.w.WriteField(name, value)
+}
+
+// WriteJSON adds a form field holding v JSON encoded.
+func (m *Multipart) WriteJSON(name string, v interface{}) error {
+	if m.closed {
+		return errMultipartClosed
+	}
+	p, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error encoding multipart field %s: %s", name, err)
+	}
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(name)))
+	h.Set(requestHeaderKeyContentType, contentTypeJSON)
+	part, err := m.w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(p)
+	return err
+}
+
+// WriteFile adds a file read from content. contentType defaults to
+// application/octet-stream.
+func (m *Multipart) WriteFile(name, filename, contentType string, content io.Reader) error {
+	if m.closed {
+		return errMultipartClosed
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(name), escapeQuotes(filename)))
+	h.Set(requestHeaderKeyContentType, contentType)
+	part, err := m.w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return fmt.Errorf("error reading multipart file %s: %s", filename, err)
+	}
+	return nil
+}
+
+// ContentType returns the Content-Type of the body, with its boundary.
+func (m *Multipart) ContentType() string {
+	return m.w.FormDataContentType()
+}
+
+// reader closes the body and returns its bytes.
+func (m *Multipart) reader() (*bytes.Reader, error) {
+	if !m.closed {
+		if err := m.w.Close(); err != nil {
+			return nil, err
+		}
+		m.closed = true
+	}
+	return bytes.NewReader(m.buf.Bytes()), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Multipart(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"1"}, r.MultipartForm.Value["version"])
+		assert.Equal(t, []string{`{"id":42,"created":"yes"}`}, r.MultipartForm.Value["data"])
+
+		files := r.MultipartForm.File["passport"]
+		if assert.Len(t, files, 1) {
+			assert.Equal(t, `scan "1".pdf`, files[0].Filename)
+			assert.Equal(t, "application/pdf", files[0].Header.Get("Content-Type"))
+			f, err := files[0].Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, _ := ioutil.ReadAll(f)
+			assert.Equal(t, "%PDF", string(content))
+		}
+		if assert.Len(t, r.MultipartForm.File["other"], 1) {
+			assert.Equal(t, "application/octet-stream",
+				r.MultipartForm.File["other"][0].Header.Get("Content-Type"))
+		}
+
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	body := NewMultipart()
+	assert.NoError(t, body.WriteField("version", "1"))
+	assert.NoError(t, body.WriteJSON("data", &basicOutputType{ID: 42, Created: "yes"}))
+	assert.NoError(t, body.WriteFile("passport", `scan "1".pdf`, "application/pdf",
+		strings.NewReader("%PDF")))
+	assert.NoError(t, body.WriteFile("other", "notes", "", strings.NewReader("notes")))
+
+	cl := NewClient(Config{BaseURL: ts.URL, Retryer: &testRetryer{maxRetries: 1}})
+	req, err := cl.NewRequest(Operation{HTTPMethod: "POST", HTTPPath: "/test/path"}, nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(req.HTTPRequest.Header.Get("Content-Type"),
+		"multipart/form-data; boundary="))
+
+	if err := req.Send(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, errMultipartClosed, body.WriteField("late", "field"))
+}
+
+func Test_MultipartEncodingFailure(t *testing.T) {
+	assert.Error(t, NewMultipart().WriteJSON("data", make(chan int)))
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the histogram buckets
+// of PrometheusMetrics. They cover request durations as well as retry
+// delays, which can reach minutes with Retry-After.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// metricHelp describes the metrics reported by requests.
+var metricHelp = map[string]string{
+	MetricRequests:         "Routefusion requests by operation and status.",
+	MetricRequestDuration:  "Duration of Routefusion requests in seconds, retries included.",
+	MetricRetries:          "Retried Routefusion request attempts by operation.",
+	MetricRetryDelay:       "Delay before retrying Routefusion requests in seconds.",
+	MetricErrors:           "Failed Routefusion requests by operation and error code.",
+	MetricInFlightRequests: "Routefusion requests being sent.",
+}
+
+type metricKind string
+
+const (
+	kindCounter   metricKind = "counter"
+	kindGauge     metricKind = "gauge"
+	kindHistogram metricKind = "histogram"
+)
+
+// PrometheusMetrics is a Metrics keeping the metrics in memory and serving
+// them in the Prometheus text format. A metric keeps the kind it was first
+// reported as; values reported as another kind are dropped.
+//
+// The zero value is ready to use, with DefaultBuckets.
+type PrometheusMetrics struct {
+	// Buckets are the upper bounds of the histogram buckets, in increasing
+	// order. Defaults to DefaultBuckets. They must not change once
+	// histograms are observed.
+	Buckets []float64
+
+	mu       sync.Mutex
+	families map[string]*metricFamily
+}
+
+type metricFamily struct {
+	kind   metricKind
+	series map[string]*metricSeries
+}
+
+type metricSeries struct {
+	labels string
+	value  float64
+
+	// counts, sum and value (the count) of histograms.
+	counts []uint64
+	sum    float64
+}
+
+// AddCounter implements Metrics.
+func (p *PrometheusMetrics) AddCounter(name string, value float64, labels ...Label) {
+	p.update(name, kindCounter, labels, func(s *metricSeries) {
+		s.value += value
+	})
+}
+
+// AddGauge implements Metrics.
+func (p *PrometheusMetrics) AddGauge(name string, value float64, labels ...Label) {
+	p.update(name, kindGauge, labels, func(s *metricSeries) {
+		s.value += value
+	})
+}
+
+// ObserveHistogram implements Metrics.
+func (p *PrometheusMetrics) ObserveHistogram(name string, value float64, labels ...Label) {
+	buckets := p.buckets()
+	p.update(name, kindHistogram, labels, func(s *metricSeries) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(buckets))
+		}
+		for i, upper := range buckets {
+			if value <= upper {
+				s.counts[i]++
+			}
+		}
+		s.sum += value
+		s.value++
+	})
+}
+
+func (p *PrometheusMetrics) buckets() []float64 {
+	if p.Buckets == nil {
+		return DefaultBuckets
+	}
+	return p.Buckets
+}
+
+// update applies fn to the series of name with labels, under the lock.
+func (p *PrometheusMetrics) update(name string, kind metricKind, labels []Label,
+	fn func(*metricSeries)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.families == nil {
+		p.families = map[string]*metricFamily{}
+	}
+	f, ok := p.families[name]
+	if !ok {
+		f = &metricFamily{kind: kind, series: map[string]*metricSeries{}}
+		p.families[name] = f
+	}
+	if f.kind != kind {
+		return
+	}
+
+	key := formatLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &metricSeries{labels: key}
+		f.series[key] = s
+	}
+	fn(s)
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(p.text())
+}
+
+// text formats the metrics, sorted by name and labels.
+func (p *PrometheusMetrics) text() []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.families))
+	for name := range p.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		f := p.families[name]
+		if help, ok := metricHelp[name]; ok {
+			fmt.Fprintf(&buf, "# HELP %s %s\n", name, help)
+		}
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != kindHistogram {
+				fmt.Fprintf(&buf, "%s%s %s\n", name, braces(s.labels), formatFloat(s.value))
+				continue
+			}
+			for i, upper := range p.buckets() {
+				fmt.Fprintf(&buf, "%s_bucket%s %d\n", name,
+					braces(joinLabels(s.labels, `le="`+formatFloat(upper)+`"`)), s.counts[i])
+			}
+			fmt.Fprintf(&buf, "%s_bucket%s %s\n", name,
+				braces(joinLabels(s.labels, `le="+Inf"`)), formatFloat(s.value))
+			fmt.Fprintf(&buf, "%s_sum%s %s\n", name, braces(s.labels), formatFloat(s.sum))
+			fmt.Fprintf(&buf, "%s_count%s %s\n", name, braces(s.labels), formatFloat(s.value))
+		}
+	}
+	return buf.Bytes()
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels formats labels as name="value" pairs, sorted by name.
+func formatLabels(labels []Label) string {
+	pairs := make([]string, len(labels))
+	for i, l := range labels {
+		pairs[i] = l.Name + `="` + labelValueReplacer.Replace(l.Value) + `"`
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func joinLabels(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+	return labels + "," + label
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
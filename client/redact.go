@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// redacted replaces the secrets in logged headers and bodies.
+const redacted = "[REDACTED]"
+
+// redactedHeaders are the headers whose values are never logged.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// redactedKeys are the JSON keys whose values are never logged, lower cased
+// and without underscores so that snake_case and camelCase keys match:
+// account numbers, tax IDs, KYC ID numbers and credentials.
+var redactedKeys = map[string]bool{
+	"accountnumber":       true,
+	"iban":                true,
+	"clabe":               true,
+	"routingnumber":       true,
+	"bsbnumber":           true,
+	"taxnumber":           true,
+	"cpfcnpj":             true,
+	"idnumber":            true,
+	"incorporationnumber": true,
+	"password":            true,
+	"token":               true,
+	"secret":              true,
+}
+
+func isRedactedKey(key string) bool {
+	return redactedKeys[strings.ToLower(strings.Replace(key, "_", "", -1))]
+}
+
+// redactHeaders formats h, one "Key: value" per line in key order, with the
+// values of sensitive headers replaced.
+func redactHeaders(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range h[k] {
+			if redactedHeaders[http.CanonicalHeaderKey(k)] {
+				v = redacted
+			}
+			fmt.Fprintf(&b, "%s: %s\n", k, v)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// redactBody returns body as it can be logged. The values of sensitive keys
+// of JSON bodies are replaced. Other bodies, such as multipart forms or batch
+// files, can hold anything and are only logged by size.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if strings.HasPrefix(contentType, "multipart/") {
+		return fmt.Sprintf("<multipart body of %d bytes>", len(body))
+	}
+
+	if json.Valid(body) {
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+		var v interface{}
+		if err := d.Decode(&v); err == nil {
+			if p, err := json.Marshal(redactValue(v)); err == nil {
+				return string(p)
+			}
+		}
+	}
+	return fmt.Sprintf("<body of %d bytes>", len(body))
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if isRedactedKey(k) && e != nil {
+				v[k] = redacted
+			} else {
+				v[k] = redactValue(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = redactValue(e)
+		}
+	}
+	return v
+}
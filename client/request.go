@@ -1,19 +1,25 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"path"
+	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	requestHeaderKeyAccept = "Accept"
+	requestHeaderKeyAccept      = "Accept"
+	requestHeaderKeyContentType = "Content-Type"
+	responseHeaderKeyRequestID  = "X-Request-Id"
+
+	contentTypeJSON = "application/json"
 )
 
 // A Request is the service request to be made.
@@ -24,50 +30,153 @@ type Request struct {
 	HTTPResponse *http.Response
 	Error        RequestFailureError
 	Output       interface{}
+	Operation    Operation
+	Authorizer   Authorizer
 	Retryer      Retryer
 	RetryCount   int
 
+	// Handlers are the phases the request goes through when it is sent.
+	Handlers Handlers
+
+	// LastErr is the transport error of the latest attempt, if any. It is
+	// set before the Retryer is consulted.
+	LastErr error
+
+	// AttemptLatency is the time the latest attempt took.
+	AttemptLatency time.Duration
+
+	// Retryable and RetryDelay are set by the Retry handlers when the latest
+	// attempt is to be retried after RetryDelay.
+	Retryable  bool
+	RetryDelay time.Duration
+
+	// Logger, when set, receives a log entry for every attempt, and the
+	// redacted headers and bodies if LogBodies is set.
+	Logger    Logger
+	LogBodies bool
+
+	// Tracer, when set, traces the operation and every attempt.
+	Tracer Tracer
+
+	// Metrics, when set, receives the metrics of the request.
+	Metrics Metrics
+
+	traceCtx      context.Context
+	operationSpan Span
+	attemptSpan   Span
+	metricsStart  time.Time
+
 	body   io.ReadSeeker
 	client *http.Client
 }
 
 // An Operation is the service API operation to be made
 type Operation struct {
+	// Name identifies the operation in traces and metrics. It defaults to
+	// the HTTP method and path.
+	Name string
+
 	HTTPMethod string
-	HTTPPath   string
+
+	// HTTPPath is the path of the operation relative to the base URL, with
+	// its segments escaped, e.g. with url.PathEscape. It is sent as is, not
+	// cleaned, so that escaped arguments cannot point to another path.
+	HTTPPath string
+
+	// RequiresIdempotencyKey makes the request carry an idempotency key on
+	// every attempt. The key is taken from the request context, see
+	// WithIdempotencyKey, or generated. Requests of other operations never
+	// carry one.
+	RequiresIdempotencyKey bool
+}
+
+// name returns the name of the operation, its HTTP method and path if it is
+// not named.
+func (o Operation) name() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.HTTPMethod + " " + o.HTTPPath
 }
 
 // NewRequest returns a new request. It is intended to be a shoot once and forget
 // object. While Send() is threadsafe, multiple calls in goroutines
 // for a single Request will not make sense because it retries inherently.
+//
+// The body can be nil, an io.ReadSeeker or a []byte which are sent as is, a
+// *Multipart which is sent as multipart/form-data, or any other value which is
+// sent JSON encoded with the matching Content-Type. An io.ReadSeeker with a
+// ContentType() string method is sent with that Content-Type.
 func NewRequest(client *http.Client,
 	retryer Retryer,
 	authorizer Authorizer,
 	baseURL string,
 	op Operation,
 	output interface{},
-	body io.ReadSeeker,
+	body interface{},
+	params map[string]string) (*Request, error) {
+	return NewRequestWithContext(context.Background(), client, retryer,
+		authorizer, baseURL, op, output, body, params)
+}
+
+// NewRequestWithContext is like NewRequest but binds the request to ctx.
+// Cancelling ctx aborts both the in-flight HTTP call and any retry back-off.
+func NewRequestWithContext(ctx context.Context,
+	client *http.Client,
+	retryer Retryer,
+	authorizer Authorizer,
+	baseURL string,
+	op Operation,
+	output interface{},
+	body interface{},
 	params map[string]string) (*Request, error) {
+	payload, contentType, err := encodeBody(body)
+	if err != nil {
+		return nil, err
+	}
 
 	finalURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid endpoint or HTTPPath supplied: %s", err)
 	}
 
-	finalURL.Path = path.Join(finalURL.Path, op.HTTPPath)
-	httpReq, err := http.NewRequest(op.HTTPMethod, finalURL.String(), nil)
+	escapedPath := joinPath(finalURL.EscapedPath(), op.HTTPPath)
+	unescapedPath, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint or HTTPPath supplied: %s", err)
+	}
+	finalURL.Path, finalURL.RawPath = unescapedPath, escapedPath
+	httpReq, err := http.NewRequestWithContext(ctx, op.HTTPMethod,
+		finalURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("error making new request: %s", err)
 	}
-	if authorizer != nil {
-		authorizer.AuthorizeRequest(httpReq)
+	if contentType != "" {
+		httpReq.Header.Set(requestHeaderKeyContentType, contentType)
+	}
+	if r, ok := payload.(interface{ Size() int64 }); ok {
+		httpReq.ContentLength = r.Size()
 	}
+
+	if op.RequiresIdempotencyKey {
+		key := IdempotencyKeyFromContext(ctx)
+		if key == "" {
+			if key, err = NewIdempotencyKey(); err != nil {
+				return nil, err
+			}
+		}
+		httpReq.Header.Set(HeaderKeyIdempotencyKey, key)
+	}
+
 	unpackParams(httpReq, params)
 
 	return &Request{
 		Output:      output,
+		Operation:   op,
 		HTTPRequest: httpReq,
-		body:        body,
+		Handlers:    DefaultHandlers(),
+		body:        payload,
+		Authorizer:  authorizer,
 		Retryer:     retryer,
 		client:      client,
 	}, nil
@@ -78,71 +187,208 @@ func NewRequest(client *http.Client,
 // of attempts.
 // An error message of format `http request failed after 0 attempts: ...` means
 // that at least one regular HTTP request but no (zero) further attempts were made.
+//
+// Send uses the context the request was created with, see SendWithContext.
 func (r *Request) Send() (err error) {
 	r.Lock()
 	defer r.Unlock()
 
-	for try := 0; ; try++ {
-		if r.body != nil {
-			r.HTTPRequest.Body = newOffsetReader(r.body, 0)
-		}
+	return r.finish(r.send())
+}
+
+// SendWithContext is like Send but replaces the request's context with ctx.
+// If ctx is cancelled or its deadline passes the request is aborted, even
+// while it is waiting to be retried, with an ErrCodeRequestCanceled error.
+func (r *Request) SendWithContext(ctx context.Context) error {
+	if ctx == nil {
+		return NewRequestFailureError(NewRFError(ErrCodeUndefined,
+			"nil context", nil), 0, "")
+	}
+
+	r.Lock()
+	defer r.Unlock()
 
-		r.HTTPResponse, err = r.client.Do(r.HTTPRequest)
+	r.HTTPRequest = r.HTTPRequest.WithContext(ctx)
+	return r.finish(r.send())
+}
+
+// IdempotencyKey returns the idempotency key sent with every attempt of the
+// request. It is empty if the request is sent without one.
+func (r *Request) IdempotencyKey() string {
+	return r.HTTPRequest.Header.Get(HeaderKeyIdempotencyKey)
+}
+
+// finish records the final outcome of the request on Error.
+func (r *Request) finish(err error) error {
+	switch e := err.(type) {
+	case *requestError:
+		e.idempotencyKey = r.IdempotencyKey()
+	case *serviceError:
+		e.idempotencyKey = r.IdempotencyKey()
+	}
 
-		if r.Retryer.ShouldRetry(r) && try < r.Retryer.MaxRetries() {
-			r.RetryCount++
-			time.Sleep(r.Retryer.RetryRules(r))
-			continue
+	r.Error = nil
+	if rerr, ok := err.(RequestFailureError); ok {
+		r.Error = rerr
+	}
+	return err
+}
+
+// send runs the handlers of the request: Build once, then Sign, Send,
+// ValidateResponse and Retry for every attempt until the request is no longer
+// retryable, then Unmarshal on success and Complete.
+func (r *Request) send() error {
+	ctx := r.HTTPRequest.Context()
+
+	r.Error = nil
+	r.Handlers.Build.Run(r)
+	for r.Error == nil {
+		r.HTTPResponse, r.LastErr = nil, nil
+		r.Retryable, r.RetryDelay = false, 0
+
+		r.Handlers.Sign.Run(r)
+		if r.Error == nil {
+			r.Handlers.Send.Run(r)
+		}
+		if r.Error == nil {
+			r.Handlers.ValidateResponse.Run(r)
+		}
+		r.Handlers.Retry.Run(r)
+		if !r.Retryable {
+			break
 		}
 
-		if err != nil {
-			code := ErrCodeUndefined
-			msg := fmt.Sprintf("http request failed after %d attempts", try)
-			if uerr, ok := err.(*url.Error); ok && uerr.Timeout() {
-				code = ErrCodeTimeout
-			}
-			return NewRequestFailureError(NewRFError(code, msg, err), 0, "")
+		if r.HTTPResponse != nil {
+			r.HTTPResponse.Body.Close()
 		}
+		if serr := sleepWithContext(ctx, r.RetryDelay); serr != nil {
+			msg := fmt.Sprintf("request canceled after %d attempts", r.RetryCount-1)
+			r.Error = NewRequestFailureError(NewRFError(
+				ErrCodeRequestCanceled, msg, serr), 0, "")
+			break
+		}
+		r.Error = nil
+	}
 
-		if r.HTTPResponse.StatusCode < http.StatusOK ||
-			r.HTTPResponse.StatusCode > http.StatusIMUsed {
-			msg := fmt.Sprintf("http request failed after %d attempts", try)
-			if r.HTTPResponse.StatusCode == http.StatusNotFound {
-				if r.HTTPResponse != nil {
-					r.HTTPResponse.Body.Close()
-				}
-				return NewRequestFailureError(NewRFError(
-					ErrCodeNotFound, msg, nil),
-					r.HTTPResponse.StatusCode,
-					"")
-			}
+	if r.Error == nil {
+		r.Handlers.Unmarshal.Run(r)
+	}
+	r.Handlers.Complete.Run(r)
+
+	if r.Error == nil {
+		return nil
+	}
+	return r.Error
+}
 
-			return NewRequestFailureError(
-				NewRFError(ErrCodeUndefined, msg, err),
-				r.HTTPResponse.StatusCode,
-				"")
+// SendHandler is the Send handler making the HTTP call of an attempt.
+var SendHandler = NamedHandler{Name: "client.Send", Fn: func(r *Request) {
+	if r.body != nil {
+		r.HTTPRequest.Body = newOffsetReader(r.body, 0)
+	}
+
+	start := time.Now()
+	r.HTTPResponse, r.LastErr = r.client.Do(r.HTTPRequest)
+	r.AttemptLatency = time.Since(start)
+}}
+
+// ValidateResponseHandler is the ValidateResponse handler turning transport
+// errors and non 2xx responses into the Error of the request.
+var ValidateResponseHandler = NamedHandler{Name: "client.ValidateResponse", Fn: func(r *Request) {
+	msg := fmt.Sprintf("http request failed after %d attempts", r.RetryCount)
+	if err := r.LastErr; err != nil {
+		code := ErrCodeUndefined
+		if ctxErr := r.HTTPRequest.Context().Err(); ctxErr != nil {
+			code = ErrCodeRequestCanceled
+			err = ctxErr
+		} else if uerr, ok := err.(*url.Error); ok && uerr.Timeout() {
+			code = ErrCodeTimeout
 		}
+		r.Error = NewRequestFailureError(NewRFError(code, msg, err), 0, "")
+		return
+	}
+	if r.HTTPResponse == nil {
+		return
+	}
 
-		if r.Output != nil {
-			err = r.unmarshalBody()
-			if err != nil {
-				return NewRequestFailureError(
-					NewRFError(ErrCodeUnmarshalFailed, "unmarshal failed", err),
-					r.HTTPResponse.StatusCode,
-					"")
-			}
+	if r.HTTPResponse.StatusCode < http.StatusOK ||
+		r.HTTPResponse.StatusCode > http.StatusIMUsed {
+		code := ErrCodeUndefined
+		if r.HTTPResponse.StatusCode == http.StatusNotFound {
+			code = ErrCodeNotFound
 		}
+		r.Error = r.serviceError(NewRFError(code, msg, nil))
+	}
+}}
+
+// failed reports whether the latest attempt failed, in transport or with a
+// non 2xx status.
+func (r *Request) failed() bool {
+	return r.Error != nil || r.LastErr != nil || r.HTTPResponse == nil ||
+		r.HTTPResponse.StatusCode < http.StatusOK ||
+		r.HTTPResponse.StatusCode > http.StatusIMUsed
+}
+
+// serviceError reads the error payload of a failed response and returns it
+// wrapped around err.
+func (r *Request) serviceError(err RFError) ServiceError {
+	reqID := r.HTTPResponse.Header.Get(responseHeaderKeyRequestID)
+
+	p, rerr := r.readBody()
+	if rerr != nil {
+		p = nil
+	}
+	return NewServiceError(err, r.HTTPResponse.StatusCode, reqID, p)
+}
+
+// encodeBody turns the body of a request into a replayable io.ReadSeeker and
+// returns the Content-Type it should be sent with, if one is known.
+func encodeBody(body interface{}) (io.ReadSeeker, string, error) {
+	switch v := body.(type) {
+	case nil:
+		return nil, "", nil
+	case io.ReadSeeker:
+		if c, ok := v.(interface{ ContentType() string }); ok {
+			return v, c.ContentType(), nil
+		}
+		return v, "", nil
+	case []byte:
+		return bytes.NewReader(v), "", nil
+	case *Multipart:
+		r, err := v.reader()
+		if err != nil {
+			return nil, "", fmt.Errorf("error encoding request body: %s", err)
+		}
+		return r, v.ContentType(), nil
+	}
+
+	p, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding request body: %s", err)
+	}
+	return bytes.NewReader(p), contentTypeJSON, nil
+}
+
+// sleepWithContext waits for d to pass or ctx to be done, whichever happens
+// first. The context error is returned in the latter case.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
 
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
 		return nil
 	}
 }
 
 func (r *Request) readBody() ([]byte, error) {
+	defer r.HTTPResponse.Body.Close()
 	p, err := ioutil.ReadAll(r.HTTPResponse.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error reading response body: %s", err)
 	}
-	defer r.HTTPResponse.Body.Close()
 	return p, nil
 }
 
@@ -159,6 +405,14 @@ func (r *Request) unmarshalBody() error {
 	return json.NewDecoder(r.HTTPResponse.Body).Decode(r.Output)
 }
 
+// joinPath appends the escaped path p to the escaped path base.
+func joinPath(base, p string) string {
+	if p == "" {
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(p, "/")
+}
+
 func unpackParams(r *http.Request, params map[string]string) {
 	q := r.URL.Query()
 	for paramName, paramValue := range params {
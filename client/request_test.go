@@ -1,14 +1,18 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -49,20 +53,21 @@ func Test_Send(t *testing.T) {
 			method:     "GET",
 			path:       "/test/path",
 			response:   []byte(`{"error_code": 503, "error_message": "service unavailable"}`),
-			expectedErr: &requestError{RFError: newBaseError(ErrCodeUndefined, "http request failed after 3 attempts", nil),
-				statusCode: 503, requestID: ""},
+			expectedErr: newServiceError(newBaseError(ErrCodeUndefined, "http request failed after 3 attempts", nil),
+				503, "", []byte(`{"error_code": 503, "error_message": "service unavailable"}`)),
 			body:            strings.NewReader(`{"somebody": "bodyval"}`),
 			expectedReqBody: []byte(`{"somebody": "bodyval"}`),
 			expectedURL:     "/test/path",
 			expectedOP:      &basicOutputType{},
 		},
 		{
-			desc:            "400 error returns an error message after 0 retries",
-			statusCode:      400,
-			method:          "GET",
-			path:            "/test/path",
-			response:        []byte(`{"error_code": 400, "error_message": "bad request"}`),
-			expectedErr:     &requestError{RFError: newBaseError(ErrCodeUndefined, "http request failed after 0 attempts", nil), statusCode: 400, requestID: ""},
+			desc:       "400 error returns an error message after 0 retries",
+			statusCode: 400,
+			method:     "GET",
+			path:       "/test/path",
+			response:   []byte(`{"error_code": 400, "error_message": "bad request"}`),
+			expectedErr: newServiceError(newBaseError(ErrCodeUndefined, "http request failed after 0 attempts", nil),
+				400, "", []byte(`{"error_code": 400, "error_message": "bad request"}`)),
 			body:            nil,
 			expectedReqBody: nil,
 			expectedURL:     "/test/path",
@@ -85,7 +90,7 @@ func Test_Send(t *testing.T) {
 			method:             "GET",
 			path:               "/test/path",
 			response:           nil,
-			expectedErr:        &requestError{RFError: newBaseError(ErrCodeUndefined, "http request failed after 0 attempts", errors.New("Get : http: nil Request.URL")), statusCode: 0, requestID: ""},
+			expectedErr:        &requestError{RFError: newBaseError(ErrCodeUndefined, "http request failed after 0 attempts", &url.Error{Op: "Get", Err: errors.New("http: nil Request.URL")}), statusCode: 0, requestID: ""},
 			body:               nil,
 			expectedReqBody:    nil,
 			expectedURL:        "/test/path",
@@ -161,3 +166,291 @@ func isEqual(expectedErr error, actualErr error) bool {
 	return expectedErr.Error() == actualErr.Error()
 
 }
+
+type slowRetryer struct{}
+
+func (s *slowRetryer) RetryRules(*Request) time.Duration {
+	return time.Hour
+}
+
+func (s *slowRetryer) ShouldRetry(*Request) bool {
+	return true
+}
+
+func (s *slowRetryer) MaxRetries() int {
+	return 3
+}
+
+func Test_SendWithContext(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		retryer      Retryer
+		handler      http.HandlerFunc
+		expectedCode string
+	}{
+		{
+			desc:    "cancellation interrupts the retry back-off",
+			retryer: &slowRetryer{},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expectedCode: ErrCodeRequestCanceled,
+		},
+		{
+			desc:    "cancellation interrupts the in-flight http call",
+			retryer: DefaultRetryer{NumMaxRetries: 3},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			expectedCode: ErrCodeRequestCanceled,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			ts := httptest.NewServer(testCase.handler)
+			defer ts.Close()
+
+			cl := NewClient(Config{BaseURL: ts.URL, Retryer: testCase.retryer})
+			req, err := cl.NewRequest(Operation{HTTPMethod: "GET",
+				HTTPPath: "/test/path"}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(),
+				50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err = req.SendWithContext(ctx)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("request was not cancelled in time, took %v", elapsed)
+			}
+
+			rerr, ok := err.(RequestFailureError)
+			if !ok {
+				t.Fatalf("expected a RequestFailureError, actual: %v", err)
+			}
+			assert.Equal(t, testCase.expectedCode, rerr.Code())
+			assert.Equal(t, context.DeadlineExceeded, rerr.OrigErr())
+		})
+	}
+}
+
+func Test_NewRequestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cl := NewClient(Config{BaseURL: "http://base.url"})
+	req, err := cl.NewRequestWithContext(ctx, Operation{HTTPMethod: "GET",
+		HTTPPath: "/test/path"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = req.Send()
+	rerr, ok := err.(RequestFailureError)
+	if !ok {
+		t.Fatalf("expected a RequestFailureError, actual: %v", err)
+	}
+	assert.Equal(t, ErrCodeRequestCanceled, rerr.Code())
+	assert.Equal(t, context.Canceled, rerr.OrigErr())
+}
+
+type typedReader struct {
+	*strings.Reader
+	contentType string
+}
+
+func (r typedReader) ContentType() string {
+	return r.contentType
+}
+
+func Test_NewRequestBody(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		body                interface{}
+		expectedReqBody     string
+		expectedContentType string
+	}{
+		{
+			desc:                "go values are sent json encoded",
+			body:                &basicOutputType{ID: 42, Created: "yes"},
+			expectedReqBody:     `{"id":42,"created":"yes"}`,
+			expectedContentType: "application/json",
+		},
+		{
+			desc:            "io.ReadSeeker is sent as is",
+			body:            strings.NewReader("id,created"),
+			expectedReqBody: "id,created",
+		},
+		{
+			desc:                "io.ReadSeeker with a ContentType is sent with it",
+			body:                typedReader{strings.NewReader("id,created"), "text/csv"},
+			expectedReqBody:     "id,created",
+			expectedContentType: "text/csv",
+		},
+		{
+			desc:            "byte slices are sent as is",
+			body:            []byte("raw"),
+			expectedReqBody: "raw",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+				r *http.Request) {
+				attempts++
+				bdy, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, testCase.expectedReqBody, string(bdy))
+				assert.Equal(t, testCase.expectedContentType,
+					r.Header.Get("Content-Type"))
+
+				if attempts == 1 {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer ts.Close()
+
+			cl := NewClient(Config{BaseURL: ts.URL,
+				Retryer: &testRetryer{maxRetries: 1}})
+			req, err := cl.NewRequest(Operation{HTTPMethod: "POST",
+				HTTPPath: "/test/path"}, nil, testCase.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := req.Send(); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, 2, attempts)
+		})
+	}
+}
+
+func Test_NewRequestBodyEncodingFailure(t *testing.T) {
+	cl := NewClient(Config{BaseURL: "http://base.url"})
+	_, err := cl.NewRequest(Operation{HTTPMethod: "POST",
+		HTTPPath: "/test/path"}, nil, make(chan int))
+	assert.Error(t, err)
+}
+
+type testRetryer struct {
+	maxRetries int
+}
+
+func (t *testRetryer) RetryRules(*Request) time.Duration {
+	return time.Millisecond
+}
+
+func (t *testRetryer) ShouldRetry(r *Request) bool {
+	return r.HTTPResponse != nil && r.HTTPResponse.StatusCode >= 500
+}
+
+func (t *testRetryer) MaxRetries() int {
+	return t.maxRetries
+}
+
+func Test_SendServiceError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": "validation failed", "errors": {"currency": "is required"}}`))
+	}))
+	defer ts.Close()
+
+	cl := NewClient(Config{BaseURL: ts.URL})
+	req, err := cl.NewRequest(Operation{HTTPMethod: "POST",
+		HTTPPath: "/test/path"}, &basicOutputType{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = req.Send()
+	var serr ServiceError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected a ServiceError, actual: %v", err)
+	}
+	assert.Equal(t, http.StatusUnprocessableEntity, serr.StatusCode())
+	assert.Equal(t, "req-42", serr.RequestID())
+	assert.Equal(t, "validation failed", serr.APIMessage())
+	assert.Equal(t, []FieldError{{Field: "currency", Message: "is required"}},
+		serr.FieldErrors())
+}
+
+func Test_SendRetriesTransportErrors(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		method           string
+		idempotencyKey   string
+		expectedAttempts int
+		expectedErr      bool
+	}{
+		{
+			desc:             "GET is retried after the connection drops",
+			method:           http.MethodGet,
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "POST is not replayed after the connection drops",
+			method:           http.MethodPost,
+			expectedAttempts: 1,
+			expectedErr:      true,
+		},
+		{
+			desc:             "POST with an idempotency key is replayed",
+			method:           http.MethodPost,
+			idempotencyKey:   "key-1",
+			expectedAttempts: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var attempts int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+				r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err != nil {
+						t.Fatal(err)
+					}
+					conn.Close()
+					return
+				}
+				w.Write([]byte(`{"id": 1}`))
+			}))
+			defer ts.Close()
+
+			cl := NewClient(Config{BaseURL: ts.URL,
+				Retryer: DefaultRetryer{NumMaxRetries: 3}})
+			req, err := cl.NewRequest(Operation{HTTPMethod: testCase.method,
+				HTTPPath: "/test/path"}, &basicOutputType{}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if testCase.idempotencyKey != "" {
+				req.HTTPRequest.Header.Set(HeaderKeyIdempotencyKey,
+					testCase.idempotencyKey)
+			}
+
+			err = req.Send()
+			assert.Equal(t, testCase.expectedAttempts, int(atomic.LoadInt32(&attempts)))
+			if testCase.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, req.Error, err)
+				assert.NotNil(t, req.LastErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, req.LastErr)
+			}
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package client
 
 import (
+	"errors"
+	"io"
 	"math/rand"
+	"net"
+	"net/http"
 	"strconv"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -14,11 +19,20 @@ type Retryer interface {
 	MaxRetries() int
 }
 
+// defaultMaxRetryAfter caps the delay requested by a Retry-After header when
+// DefaultRetryer.MaxRetryAfter is not set.
+const defaultMaxRetryAfter = 5 * time.Minute
+
 // DefaultRetryer implements basic retry logic using exponential backoff for
-// most services.
+// most services. Throttled (429) and unavailable (503) responses are retried
+// after the delay requested by their Retry-After header, if any.
 type DefaultRetryer struct {
 	NumMaxRetries     int
 	MaxRetryThreshold int
+
+	// MaxRetryAfter caps the delay requested by a Retry-After header.
+	// Defaults to five minutes.
+	MaxRetryAfter time.Duration
 }
 
 // MaxRetries returns the number of maximum returns the service will use to make
@@ -31,6 +45,17 @@ var seededRand = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano(
 
 // RetryRules returns the delay duration before retrying this request again
 func (d DefaultRetryer) RetryRules(r *Request) time.Duration {
+	if delay, ok := getRetryDelay(r); ok {
+		maxRetryAfter := d.MaxRetryAfter
+		if maxRetryAfter == 0 {
+			maxRetryAfter = defaultMaxRetryAfter
+		}
+		if delay > maxRetryAfter {
+			delay = maxRetryAfter
+		}
+		return delay
+	}
+
 	// Set the upper limit of delay in retrying at ~five minutes
 	minTime := 30
 	maxRetryThreshold := d.MaxRetryThreshold
@@ -48,18 +73,100 @@ func (d DefaultRetryer) RetryRules(r *Request) time.Duration {
 }
 
 // ShouldRetry returns true if the request should be retried.
+//
+// Transport errors are retried when they are transient, e.g. timeouts,
+// connection resets or DNS failures. As the server may have processed a
+// request that failed after being sent, requests that are not idempotent,
+// such as POSTs, are only replayed if they carry an idempotency key or if
+// the connection could not even be established. The same goes for 5xx
+// responses other than 503, which tells the request was not processed.
 func (d DefaultRetryer) ShouldRetry(r *Request) bool {
+	if r.LastErr != nil {
+		return isTransientNetError(r.LastErr) &&
+			(isReplayable(r.HTTPRequest) || isDialError(r.LastErr))
+	}
 	if r.HTTPResponse == nil {
 		return false
 	}
-	if r.HTTPResponse.StatusCode >= 500 {
+	switch {
+	case r.HTTPResponse.StatusCode == http.StatusTooManyRequests,
+		r.HTTPResponse.StatusCode == http.StatusServiceUnavailable:
 		return true
+	case r.HTTPResponse.StatusCode >= 500:
+		return isReplayable(r.HTTPRequest)
 	}
 	return false
 }
 
+// RetryHandler is the Retry handler asking the Retryer of the request whether
+// the latest attempt is to be retried, and after which delay. Requests whose
+// context is done are never retried.
+var RetryHandler = NamedHandler{Name: "client.Retry", Fn: func(r *Request) {
+	if r.Retryer == nil || r.HTTPRequest.Context().Err() != nil ||
+		r.RetryCount >= r.Retryer.MaxRetries() || !r.Retryer.ShouldRetry(r) {
+		return
+	}
+	r.Retryable = true
+	r.RetryCount++
+	r.RetryDelay = r.Retryer.RetryRules(r)
+}}
+
+// isTransientNetError reports whether err is a network failure that is worth
+// trying again.
+func isTransientNetError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	// Connections failing while being opened, read or written are worth
+	// retrying, unlike the other operations such as TLS alerts sent by the
+	// server ("remote error"), which fail the same way every time.
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		switch opErr.Op {
+		case "dial", "read", "write":
+			return true
+		}
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isDialError reports whether err happened while connecting, i.e. before
+// anything was sent to the server.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isReplayable reports whether sending r more than once has the same effect
+// as sending it once.
+func isReplayable(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return r.Header.Get(HeaderKeyIdempotencyKey) != ""
+}
+
 // This will look in the Retry-After header, RFC 7231, for how long
-// it will wait before attempting another request
+// it will wait before attempting another request. Both the delay-seconds and
+// the HTTP-date forms are supported.
 func getRetryDelay(r *Request) (time.Duration, bool) {
 	if !canUseRetryAfterHeader(r) {
 		return 0, false
@@ -70,17 +177,32 @@ func getRetryDelay(r *Request) (time.Duration, bool) {
 		return 0, false
 	}
 
-	delay, err := strconv.Atoi(delayStr)
+	if delay, err := strconv.Atoi(delayStr); err == nil {
+		if delay < 0 {
+			return 0, false
+		}
+		return time.Duration(delay) * time.Second, true
+	}
+
+	date, err := http.ParseTime(delayStr)
 	if err != nil {
 		return 0, false
 	}
 
-	return time.Duration(delay) * time.Second, true
+	delay := time.Until(date)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
 }
 
 // Will look at the status code to see if the retry header pertains to
 // the status code.
 func canUseRetryAfterHeader(r *Request) bool {
+	if r.HTTPResponse == nil {
+		return false
+	}
+
 	switch r.HTTPResponse.StatusCode {
 	case 429:
 	case 503:
@@ -1,7 +1,11 @@
 package client
 
 import (
+	"errors"
+	"net"
 	"net/http"
+	"net/url"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -145,3 +149,217 @@ func TestGetRetryDelay(t *testing.T) {
 		}
 	}
 }
+
+func TestGetRetryDelayHTTPDate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		date     time.Time
+		minDelay time.Duration
+		maxDelay time.Duration
+	}{
+		{
+			desc:     "date in the future",
+			date:     time.Now().Add(2 * time.Minute),
+			minDelay: 1 * time.Minute,
+			maxDelay: 2 * time.Minute,
+		},
+		{
+			desc:     "date in the past",
+			date:     time.Now().Add(-2 * time.Minute),
+			minDelay: 0,
+			maxDelay: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			r := &Request{HTTPResponse: &http.Response{StatusCode: 429,
+				Header: http.Header{"Retry-After": []string{
+					testCase.date.UTC().Format(http.TimeFormat)}}}}
+
+			delay, ok := getRetryDelay(r)
+			if !ok {
+				t.Fatal("expected the HTTP-date to be accepted")
+			}
+			if delay < testCase.minDelay || delay > testCase.maxDelay {
+				t.Errorf("delay %v not within [%v, %v]", delay,
+					testCase.minDelay, testCase.maxDelay)
+			}
+		})
+	}
+}
+
+func Test_RetryRulesRetryAfter(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		retryer       DefaultRetryer
+		statusCode    int
+		retryAfter    string
+		expectedDelay time.Duration
+	}{
+		{
+			desc:          "delay-seconds are respected",
+			retryer:       DefaultRetryer{NumMaxRetries: 3},
+			statusCode:    429,
+			retryAfter:    "7",
+			expectedDelay: 7 * time.Second,
+		},
+		{
+			desc:          "delay is capped by the configured maximum",
+			retryer:       DefaultRetryer{NumMaxRetries: 3, MaxRetryAfter: 10 * time.Second},
+			statusCode:    503,
+			retryAfter:    "3600",
+			expectedDelay: 10 * time.Second,
+		},
+		{
+			desc:          "delay is capped by the default maximum",
+			retryer:       DefaultRetryer{NumMaxRetries: 3},
+			statusCode:    503,
+			retryAfter:    "3600",
+			expectedDelay: defaultMaxRetryAfter,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			r := &Request{HTTPResponse: &http.Response{
+				StatusCode: testCase.statusCode,
+				Header:     http.Header{"Retry-After": []string{testCase.retryAfter}},
+			}}
+
+			if delay := testCase.retryer.RetryRules(r); delay != testCase.expectedDelay {
+				t.Errorf("expected %v, but received %v", testCase.expectedDelay, delay)
+			}
+		})
+	}
+}
+
+func Test_ShouldRetry(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		method         string
+		idempotencyKey string
+		response       *http.Response
+		expected       bool
+	}{
+		{desc: "no response", method: http.MethodGet, response: nil, expected: false},
+		{desc: "200", method: http.MethodGet, response: &http.Response{StatusCode: 200}, expected: false},
+		{desc: "400", method: http.MethodGet, response: &http.Response{StatusCode: 400}, expected: false},
+		{desc: "429", method: http.MethodPost, response: &http.Response{StatusCode: 429}, expected: true},
+		{desc: "503", method: http.MethodPost, response: &http.Response{StatusCode: 503}, expected: true},
+		{desc: "500 on GET", method: http.MethodGet, response: &http.Response{StatusCode: 500}, expected: true},
+		{desc: "500 on POST", method: http.MethodPost, response: &http.Response{StatusCode: 500}, expected: false},
+		{desc: "500 on POST with an idempotency key", method: http.MethodPost,
+			idempotencyKey: "key-1", response: &http.Response{StatusCode: 500}, expected: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			httpReq, _ := http.NewRequest(testCase.method, "http://base.url", nil)
+			if testCase.idempotencyKey != "" {
+				httpReq.Header.Set(HeaderKeyIdempotencyKey, testCase.idempotencyKey)
+			}
+
+			d := DefaultRetryer{NumMaxRetries: 3}
+			r := &Request{HTTPRequest: httpReq, HTTPResponse: testCase.response}
+			if actual := d.ShouldRetry(r); actual != testCase.expected {
+				t.Errorf("expected %v, but received %v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func Test_ShouldRetryTransportErrors(t *testing.T) {
+	reset := &url.Error{Op: "Post", URL: "http://base.url",
+		Err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}}
+
+	testCases := []struct {
+		desc           string
+		method         string
+		idempotencyKey string
+		err            error
+		expected       bool
+	}{
+		{
+			desc:     "connection reset on GET",
+			method:   http.MethodGet,
+			err:      reset,
+			expected: true,
+		},
+		{
+			desc:     "connection reset on POST without an idempotency key",
+			method:   http.MethodPost,
+			err:      reset,
+			expected: false,
+		},
+		{
+			desc:           "connection reset on POST with an idempotency key",
+			method:         http.MethodPost,
+			idempotencyKey: "key-1",
+			err:            reset,
+			expected:       true,
+		},
+		{
+			desc:   "dial failure on POST",
+			method: http.MethodPost,
+			err: &url.Error{Op: "Post", URL: "http://base.url",
+				Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}},
+			expected: true,
+		},
+		{
+			desc:   "tls alert on GET",
+			method: http.MethodGet,
+			err: &url.Error{Op: "Get", URL: "http://base.url",
+				Err: &net.OpError{Op: "remote error",
+					Err: errors.New("tls: handshake failure")}},
+			expected: false,
+		},
+		{
+			desc:     "timeout on GET",
+			method:   http.MethodGet,
+			err:      &url.Error{Op: "Get", URL: "http://base.url", Err: timeoutError{}},
+			expected: true,
+		},
+		{
+			desc:   "temporary dns failure on GET",
+			method: http.MethodGet,
+			err: &url.Error{Op: "Get", URL: "http://base.url",
+				Err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}},
+			expected: true,
+		},
+		{
+			desc:   "unknown host on GET",
+			method: http.MethodGet,
+			err: &url.Error{Op: "Get", URL: "http://base.url",
+				Err: &net.DNSError{Err: "no such host", IsNotFound: true}},
+			expected: false,
+		},
+		{
+			desc:     "non network error on GET",
+			method:   http.MethodGet,
+			err:      &url.Error{Op: "Get", Err: errors.New("http: nil Request.URL")},
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			httpReq, _ := http.NewRequest(testCase.method, "http://base.url", nil)
+			if testCase.idempotencyKey != "" {
+				httpReq.Header.Set(HeaderKeyIdempotencyKey, testCase.idempotencyKey)
+			}
+			r := &Request{HTTPRequest: httpReq, LastErr: testCase.err}
+
+			d := DefaultRetryer{NumMaxRetries: 3}
+			if actual := d.ShouldRetry(r); actual != testCase.expected {
+				t.Errorf("expected %v, but received %v", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HeaderKeyTraceParent is the W3C Trace Context header carrying the trace
+// and the parent span of a request.
+const HeaderKeyTraceParent = "traceparent"
+
+// Tracer starts the spans of requests. A request is traced with a span per
+// operation, named after Operation.Name, and a child span per HTTP attempt,
+// named "HTTP <method>". Spans carry the operation, method, path, attempt,
+// status code, retry count and error code as fields.
+//
+// Implementations must be safe for concurrent use. An OpenTelemetry tracer
+// can be adapted by mapping fields to attributes and Inject to a propagator.
+type Tracer interface {
+	// Start starts a span, the child of the span carried by ctx if any, and
+	// returns a copy of ctx carrying it.
+	Start(ctx context.Context, name string, fields ...Field) (context.Context, Span)
+
+	// Inject sets the headers propagating the span carried by ctx, e.g.
+	// traceparent.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is an operation or an attempt being traced.
+type Span interface {
+	SetFields(fields ...Field)
+	SetError(err error)
+	End()
+}
+
+// StartOperationSpanHandler is the Build handler starting the span of the
+// operation of the request.
+var StartOperationSpanHandler = NamedHandler{Name: "client.StartOperationSpan", Fn: func(r *Request) {
+	if r.Tracer == nil {
+		return
+	}
+
+	r.traceCtx, r.operationSpan = r.Tracer.Start(r.HTTPRequest.Context(), r.Operation.name(),
+		Field{Key: "operation", Value: r.Operation.name()},
+		Field{Key: "http.method", Value: r.HTTPRequest.Method},
+		Field{Key: "http.path", Value: r.HTTPRequest.URL.Path})
+}}
+
+// StartAttemptSpanHandler is the Sign handler starting the span of an
+// attempt and setting the headers propagating it.
+var StartAttemptSpanHandler = NamedHandler{Name: "client.StartAttemptSpan", Fn: func(r *Request) {
+	if r.Tracer == nil || r.operationSpan == nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, r.attemptSpan = r.Tracer.Start(r.traceCtx, "HTTP "+r.HTTPRequest.Method,
+		Field{Key: "http.method", Value: r.HTTPRequest.Method},
+		Field{Key: "http.path", Value: r.HTTPRequest.URL.Path},
+		Field{Key: "attempt", Value: r.RetryCount + 1})
+	r.Tracer.Inject(ctx, r.HTTPRequest.Header)
+}}
+
+// EndAttemptSpanHandler is the Retry handler ending the span of an attempt.
+var EndAttemptSpanHandler = NamedHandler{Name: "client.EndAttemptSpan", Fn: func(r *Request) {
+	if r.attemptSpan == nil {
+		return
+	}
+
+	r.attemptSpan.SetFields(r.traceFields()...)
+	if r.Error != nil {
+		r.attemptSpan.SetError(r.Error)
+	}
+	r.attemptSpan.End()
+	r.attemptSpan = nil
+}}
+
+// EndOperationSpanHandler is the Complete handler ending the span of the
+// operation of the request.
+var EndOperationSpanHandler = NamedHandler{Name: "client.EndOperationSpan", Fn: func(r *Request) {
+	if r.operationSpan == nil {
+		return
+	}
+
+	r.operationSpan.SetFields(r.traceFields()...)
+	if r.Error != nil {
+		r.operationSpan.SetError(r.Error)
+	}
+	r.operationSpan.End()
+	r.operationSpan = nil
+}}
+
+// traceFields returns the fields describing the outcome of the latest
+// attempt.
+func (r *Request) traceFields() []Field {
+	fields := []Field{{Key: "retry_count", Value: r.RetryCount}}
+	if r.HTTPResponse != nil {
+		fields = append(fields, Field{Key: "http.status_code", Value: r.HTTPResponse.StatusCode})
+	}
+	if r.Error != nil {
+		fields = append(fields, Field{Key: "error_code", Value: r.Error.Code()})
+	}
+	return fields
+}
+
+// RecordingTracer is a Tracer keeping the spans it starts in memory, for
+// tests. It propagates spans with the traceparent header.
+type RecordingTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// RecordedSpan is a span started by a RecordingTracer.
+type RecordedSpan struct {
+	Name     string
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Fields   []Field
+	Err      error
+	Start    time.Time
+	End      time.Time
+}
+
+type recordedSpanContextKey struct{}
+
+// Start implements Tracer.
+func (t *RecordingTracer) Start(ctx context.Context, name string,
+	fields ...Field) (context.Context, Span) {
+	span := &recordingSpan{span: &RecordedSpan{
+		Name:    name,
+		TraceID: randomHex(16),
+		SpanID:  randomHex(8),
+		Fields:  append([]Field(nil), fields...),
+		Start:   time.Now(),
+	}, tracer: t}
+	if parent, ok := ctx.Value(recordedSpanContextKey{}).(*RecordedSpan); ok {
+		span.span.TraceID = parent.TraceID
+		span.span.ParentID = parent.SpanID
+	}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span.span)
+	t.mu.Unlock()
+	return context.WithValue(ctx, recordedSpanContextKey{}, span.span), span
+}
+
+// Inject implements Tracer.
+func (t *RecordingTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(recordedSpanContextKey{}).(*RecordedSpan); ok {
+		header.Set(HeaderKeyTraceParent, fmt.Sprintf("00-%s-%s-01", span.TraceID, span.SpanID))
+	}
+}
+
+// Spans returns a copy of the spans started so far, in the order they were
+// started.
+func (t *RecordingTracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]RecordedSpan, len(t.spans))
+	for i, s := range t.spans {
+		spans[i] = *s
+		spans[i].Fields = append([]Field(nil), s.Fields...)
+	}
+	return spans
+}
+
+// Field returns the last value set for key, if any.
+func (s RecordedSpan) Field(key string) (interface{}, bool) {
+	for i := len(s.Fields) - 1; i >= 0; i-- {
+		if s.Fields[i].Key == key {
+			return s.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// recordingSpan is the Span of a RecordingTracer.
+type recordingSpan struct {
+	span   *RecordedSpan
+	tracer *RecordingTracer
+}
+
+// SetFields implements Span.
+func (s *recordingSpan) SetFields(fields ...Field) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.Fields = append(s.span.Fields, fields...)
+}
+
+// SetError implements Span.
+func (s *recordingSpan) SetError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.Err = err
+}
+
+// End implements Span.
+func (s *recordingSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.End = time.Now()
+}
+
+// randomHex returns n random bytes, hex encoded.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
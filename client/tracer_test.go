@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TracerSpans(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		statuses          []int
+		expectedStatus    int
+		expectedErrorCode string
+	}{
+		{
+			desc:           "retried",
+			statuses:       []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:              "not found",
+			statuses:          []int{http.StatusServiceUnavailable, http.StatusNotFound},
+			expectedStatus:    http.StatusNotFound,
+			expectedErrorCode: ErrCodeNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var traceParents []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+				r *http.Request) {
+				traceParents = append(traceParents, r.Header.Get(HeaderKeyTraceParent))
+				w.WriteHeader(testCase.statuses[len(traceParents)-1])
+			}))
+			defer ts.Close()
+
+			tracer := &RecordingTracer{}
+			cl := NewClient(Config{BaseURL: ts.URL, Tracer: tracer,
+				Retryer: &testRetryer{maxRetries: 1}})
+			parentCtx, parent := tracer.Start(context.Background(), "caller")
+			req, err := cl.NewRequestWithContext(parentCtx,
+				Operation{Name: "GetThing", HTTPMethod: "GET", HTTPPath: "/v1/things/1"}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = req.Send()
+			parent.End()
+
+			spans := tracer.Spans()
+			if !assert.Len(t, spans, 4) {
+				return
+			}
+			caller, operation, attempts := spans[0], spans[1], spans[2:]
+
+			assert.Equal(t, "GetThing", operation.Name)
+			assert.Equal(t, caller.TraceID, operation.TraceID)
+			assert.Equal(t, caller.SpanID, operation.ParentID)
+			assert.False(t, operation.End.IsZero())
+			status, _ := operation.Field("http.status_code")
+			assert.Equal(t, testCase.expectedStatus, status)
+			retries, _ := operation.Field("retry_count")
+			assert.Equal(t, 1, retries)
+			code, _ := operation.Field("error_code")
+			if testCase.expectedErrorCode == "" {
+				assert.Nil(t, code)
+				assert.NoError(t, err)
+				assert.NoError(t, operation.Err)
+			} else {
+				assert.Equal(t, testCase.expectedErrorCode, code)
+				assert.Equal(t, err, operation.Err)
+			}
+
+			for i, attempt := range attempts {
+				assert.Equal(t, "HTTP GET", attempt.Name)
+				assert.Equal(t, operation.SpanID, attempt.ParentID)
+				assert.False(t, attempt.End.IsZero())
+				assert.Equal(t, fmt.Sprintf("00-%s-%s-01", attempt.TraceID, attempt.SpanID),
+					traceParents[i])
+				number, _ := attempt.Field("attempt")
+				assert.Equal(t, i+1, number)
+				status, _ := attempt.Field("http.status_code")
+				assert.Equal(t, testCase.statuses[i], status)
+			}
+			assert.Error(t, attempts[0].Err)
+		})
+	}
+}
+
+func Test_TracerOperationName(t *testing.T) {
+	tracer := &RecordingTracer{}
+	cl := NewClient(Config{BaseURL: "http://localhost", Tracer: tracer})
+	req, err := cl.NewRequest(Operation{HTTPMethod: "GET", HTTPPath: "/v1/things"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Handlers.Sign.PushBack(func(r *Request) {
+		r.Error = NewRequestFailureError(NewRFError(ErrCodeUndefined, "not signed", nil), 0, "")
+	})
+	assert.Error(t, req.Send())
+
+	spans := tracer.Spans()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "GET /v1/things", spans[0].Name)
+		assert.Error(t, spans[0].Err)
+		assert.Error(t, spans[1].Err)
+		assert.False(t, spans[1].End.IsZero())
+	}
+}
@@ -26,3 +26,18 @@ type jsonUnmarshaler struct{}
 func (j *jsonUnmarshaler) Unmarshal(r io.Reader, v interface{}) error {
 	return json.NewDecoder(r).Decode(v)
 }
+
+// UnmarshalHandler is the Unmarshal handler decoding the response into the
+// Output of the request, with the unmarshaler registered for its Accept
+// header, JSON by default.
+var UnmarshalHandler = NamedHandler{Name: "client.Unmarshal", Fn: func(r *Request) {
+	if r.Error != nil || r.Output == nil {
+		return
+	}
+	if err := r.unmarshalBody(); err != nil {
+		r.Error = NewRequestFailureError(
+			NewRFError(ErrCodeUnmarshalFailed, "unmarshal failed", err),
+			r.HTTPResponse.StatusCode,
+			r.HTTPResponse.Header.Get(responseHeaderKeyRequestID))
+	}
+}}
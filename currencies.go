@@ -0,0 +1,129 @@
+package routefusion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetCurrencies returns the currency coverage of Routefusion.
+func (a *API) GetCurrencies() (*CurrenciesResponse, error) {
+	return a.GetCurrenciesWithContext(context.Background())
+}
+
+// GetCurrenciesWithContext is like GetCurrencies but binds the request to ctx.
+func (a *API) GetCurrenciesWithContext(ctx context.Context) (*CurrenciesResponse, error) {
+	out := &CurrenciesResponse{}
+	if err := a.do(ctx, getCurrenciesEndpoint.operation(), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Currency returns the currency of the given code.
+func (c *CurrenciesResponse) Currency(code string) (*Currency, bool) {
+	for i := range c.Currencies {
+		if strings.EqualFold(c.Currencies[i].Code, code) {
+			return &c.Currencies[i], true
+		}
+	}
+	return nil, false
+}
+
+// SourceCurrencies returns the codes of the currencies transfers can be
+// funded in.
+func (c *CurrenciesResponse) SourceCurrencies() []string {
+	var codes []string
+	for _, currency := range c.Currencies {
+		if currency.Source {
+			codes = append(codes, currency.Code)
+		}
+	}
+	return codes
+}
+
+// DestinationCurrencies returns the codes of the currencies transfers can be
+// paid out in.
+func (c *CurrenciesResponse) DestinationCurrencies() []string {
+	var codes []string
+	for _, currency := range c.Currencies {
+		if currency.Destination {
+			codes = append(codes, currency.Code)
+		}
+	}
+	return codes
+}
+
+// Corridor returns the payout details in the destination currency to banks
+// of country.
+func (c *CurrenciesResponse) Corridor(destination, country string) (*BankCountry, bool) {
+	currency, ok := c.Currency(destination)
+	if !ok || !currency.Destination {
+		return nil, false
+	}
+	return currency.BankCountry(country)
+}
+
+// Supports reports whether transfers can be funded in the source currency
+// and paid out in the destination currency to banks of country.
+func (c *CurrenciesResponse) Supports(source, destination, country string) bool {
+	currency, ok := c.Currency(source)
+	if !ok || !currency.Source {
+		return false
+	}
+	_, ok = c.Corridor(destination, country)
+	return ok
+}
+
+// RequiredFields returns the JSON names of the BeneficiaryInput fields that
+// beneficiaries paid in the destination currency by banks of country need.
+// It returns nil if the corridor is not supported.
+func (c *CurrenciesResponse) RequiredFields(destination, country string) []string {
+	corridor, ok := c.Corridor(destination, country)
+	if !ok {
+		return nil
+	}
+	return corridor.RequiredFields
+}
+
+// BankCountry returns the payout details to banks of country.
+func (c *Currency) BankCountry(country string) (*BankCountry, bool) {
+	for i := range c.BankCountries {
+		if strings.EqualFold(c.BankCountries[i].Country, country) {
+			return &c.BankCountries[i], true
+		}
+	}
+	return nil, false
+}
+
+// On returns the cut-off on the date of day in the time zone of the cut-off.
+func (c CutOff) On(day time.Time) (time.Time, error) {
+	loc := time.UTC
+	if c.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(c.TimeZone); err != nil {
+			return time.Time{}, fmt.Errorf("cut-off time zone: %w", err)
+		}
+	}
+	clock, err := time.Parse("15:04", c.Time)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cut-off time: %w", err)
+	}
+
+	year, month, date := day.In(loc).Date()
+	return time.Date(year, month, date, clock.Hour(), clock.Minute(), 0, 0, loc), nil
+}
+
+// Next returns the first cut-off after now. It does not account for
+// weekends and bank holidays.
+func (c CutOff) Next(now time.Time) (time.Time, error) {
+	cutOff, err := c.On(now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !cutOff.After(now) {
+		cutOff = cutOff.AddDate(0, 0, 1)
+	}
+	return cutOff, nil
+}
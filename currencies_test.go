@@ -0,0 +1,125 @@
+package routefusion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCoverage = &CurrenciesResponse{Currencies: []Currency{
+	{Code: "USD", Source: true, Destination: true, BankCountries: []BankCountry{
+		{Country: "US", RequiredFields: []string{"account_number", "routing_number"}},
+	}},
+	{Code: "EUR", Source: true},
+	{Code: "MXN", Destination: true, BankCountries: []BankCountry{
+		{Country: "MX", RequiredFields: []string{"clabe"}},
+	}},
+}}
+
+func Test_CurrenciesResponseSupports(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		source      string
+		destination string
+		country     string
+		expected    bool
+	}{
+		{desc: "supported corridor", source: "USD", destination: "MXN", country: "MX",
+			expected: true},
+		{desc: "case insensitive", source: "eur", destination: "mxn", country: "mx",
+			expected: true},
+		{desc: "domestic", source: "USD", destination: "USD", country: "US", expected: true},
+		{desc: "unsupported source", source: "MXN", destination: "USD", country: "US"},
+		{desc: "unsupported destination", source: "USD", destination: "EUR", country: "DE"},
+		{desc: "unsupported bank country", source: "USD", destination: "MXN", country: "US"},
+		{desc: "unknown currency", source: "GBP", destination: "MXN", country: "MX"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCoverage.Supports(testCase.source,
+				testCase.destination, testCase.country))
+		})
+	}
+}
+
+func Test_CurrenciesResponseLookups(t *testing.T) {
+	assert.Equal(t, []string{"USD", "EUR"}, testCoverage.SourceCurrencies())
+	assert.Equal(t, []string{"USD", "MXN"}, testCoverage.DestinationCurrencies())
+	assert.Equal(t, []string{"clabe"}, testCoverage.RequiredFields("MXN", "MX"))
+	assert.Nil(t, testCoverage.RequiredFields("EUR", "DE"))
+
+	currency, ok := testCoverage.Currency("usd")
+	if assert.True(t, ok) {
+		assert.Equal(t, "USD", currency.Code)
+	}
+	_, ok = testCoverage.Currency("GBP")
+	assert.False(t, ok)
+}
+
+func Test_CutOffNext(t *testing.T) {
+	mexico, err := time.LoadLocation("America/Mexico_City")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	testCases := []struct {
+		desc           string
+		cutOff         CutOff
+		now            time.Time
+		expected       time.Time
+		expectedErrMsg string
+	}{
+		{
+			desc:     "later today",
+			cutOff:   CutOff{Time: "14:00", TimeZone: "America/Mexico_City"},
+			now:      time.Date(2020, 1, 2, 12, 0, 0, 0, mexico),
+			expected: time.Date(2020, 1, 2, 14, 0, 0, 0, mexico),
+		},
+		{
+			desc:     "tomorrow",
+			cutOff:   CutOff{Time: "14:00", TimeZone: "America/Mexico_City"},
+			now:      time.Date(2020, 1, 2, 14, 0, 0, 0, mexico),
+			expected: time.Date(2020, 1, 3, 14, 0, 0, 0, mexico),
+		},
+		{
+			desc:     "date in the time zone of the cut-off",
+			cutOff:   CutOff{Time: "14:00", TimeZone: "America/Mexico_City"},
+			now:      time.Date(2020, 1, 3, 3, 0, 0, 0, time.UTC),
+			expected: time.Date(2020, 1, 3, 14, 0, 0, 0, mexico),
+		},
+		{
+			desc:     "UTC by default",
+			cutOff:   CutOff{Time: "16:30"},
+			now:      time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2020, 1, 2, 16, 30, 0, 0, time.UTC),
+		},
+		{
+			desc:           "invalid time",
+			cutOff:         CutOff{Time: "4pm"},
+			expectedErrMsg: "cut-off time: ",
+		},
+		{
+			desc:           "invalid time zone",
+			cutOff:         CutOff{Time: "16:00", TimeZone: "Nowhere/City"},
+			expectedErrMsg: "cut-off time zone: ",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			next, err := testCase.cutOff.Next(testCase.now)
+			if testCase.expectedErrMsg != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), testCase.expectedErrMsg)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.True(t, testCase.expected.Equal(next), "expected %s, got %s",
+					testCase.expected, next)
+			}
+		})
+	}
+}
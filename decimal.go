@@ -0,0 +1,369 @@
+package routefusion
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var bigTen = big.NewInt(10)
+
+// maxDecimalScale bounds the exponent and the number of decimals accepted by
+// ParseDecimal, so that a hostile payload such as 1e99999999 cannot make the
+// client compute huge powers of ten.
+const maxDecimalScale = 100
+
+// Decimal is an exact decimal number used for amounts, fees and rates. The
+// zero value is 0.
+//
+// Decimals are immutable: arithmetic returns new values and never alters its
+// operands. Values marshal to JSON numbers and unmarshal from both JSON
+// numbers and strings.
+type Decimal struct {
+	// unscaled holds the digits of the number, nil meaning zero.
+	unscaled *big.Int
+
+	// scale is the number of digits after the decimal point, never
+	// negative.
+	scale int32
+}
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(12345, 2) is
+// 123.45.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	u := big.NewInt(unscaled)
+	if scale < 0 {
+		u.Mul(u, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: u, scale: scale}
+}
+
+// NewDecimalFromInt returns i as a Decimal.
+func NewDecimalFromInt(i int64) Decimal {
+	return NewDecimal(i, 0)
+}
+
+// ParseDecimal parses decimal numbers such as "-12.50" or "1.2e3". Exponents
+// and numbers of decimals beyond 100 are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		exp, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+
+	sign := ""
+	if len(mantissa) > 0 && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	u, ok := new(big.Int).SetString(sign+intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if exp > maxDecimalScale || exp < -maxDecimalScale ||
+		scale > maxDecimalScale || scale < -maxDecimalScale {
+		return Decimal{}, fmt.Errorf("decimal %q is out of range", s)
+	}
+	if scale < 0 {
+		u.Mul(u, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{unscaled: u, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is not a decimal
+// number. It is meant for constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescaled returns the unscaled value of d at the given scale, which must
+// not be lower than the scale of d.
+func (d Decimal) rescaled(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.int()
+	}
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+// align returns the unscaled values of d and e at their common scale.
+func align(d, e Decimal) (*big.Int, *big.Int, int32) {
+	scale := d.scale
+	if e.scale > scale {
+		scale = e.scale
+	}
+	return d.rescaled(scale), e.rescaled(scale), scale
+}
+
+// quoRound returns num/den rounded half away from zero.
+func quoRound(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	if new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(
+		new(big.Int).Abs(den)) >= 0 {
+		if num.Sign() == den.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Add returns d + e.
+func (d Decimal) Add(e Decimal) Decimal {
+	x, y, scale := align(d, e)
+	return Decimal{unscaled: new(big.Int).Add(x, y), scale: scale}
+}
+
+// Sub returns d - e.
+func (d Decimal) Sub(e Decimal) Decimal {
+	x, y, scale := align(d, e)
+	return Decimal{unscaled: new(big.Int).Sub(x, y), scale: scale}
+}
+
+// Mul returns d * e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{
+		unscaled: new(big.Int).Mul(d.int(), e.int()),
+		scale:    d.scale + e.scale,
+	}
+}
+
+// Div returns d / e rounded half away from zero to the given number of
+// decimal places, 0 if places is negative. It panics if e is zero.
+func (d Decimal) Div(e Decimal, places int32) Decimal {
+	if e.IsZero() {
+		panic("routefusion: division of a Decimal by zero")
+	}
+	if places < 0 {
+		places = 0
+	}
+
+	num, den := new(big.Int).Set(d.int()), new(big.Int).Set(e.int())
+	if k := places + e.scale - d.scale; k >= 0 {
+		num.Mul(num, pow10(k))
+	} else {
+		den.Mul(den, pow10(-k))
+	}
+	return Decimal{unscaled: quoRound(num, den), scale: places}
+}
+
+// Round returns d rounded half away from zero to the given number of decimal
+// places.
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d
+	}
+	return Decimal{
+		unscaled: quoRound(d.int(), pow10(d.scale-places)),
+		scale:    places,
+	}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Cmp compares d and e and returns -1, 0 or +1 if d is respectively lower
+// than, equal to or greater than e.
+func (d Decimal) Cmp(e Decimal) int {
+	x, y, _ := align(d, e)
+	return x.Cmp(y)
+}
+
+// Equal reports whether d and e are the same number, regardless of their
+// number of decimal places.
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// LessThan reports whether d < e.
+func (d Decimal) LessThan(e Decimal) bool {
+	return d.Cmp(e) < 0
+}
+
+// GreaterThan reports whether d > e.
+func (d Decimal) GreaterThan(e Decimal) bool {
+	return d.Cmp(e) > 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Float64 returns the float64 closest to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain notation with all its decimal places, e.g.
+// "-12.50".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		i := len(digits) - int(d.scale)
+		digits = digits[:i] + "." + digits[i:]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// StringFixed returns d rounded to, and padded with zeros up to, the given
+// number of decimal places.
+func (d Decimal) StringFixed(places int32) string {
+	if places < 0 {
+		places = 0
+	}
+	r := d.Round(places)
+	return Decimal{unscaled: r.rescaled(places), scale: places}.String()
+}
+
+// MarshalJSON encodes d as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes d from a JSON number or string. Null and empty
+// strings decode to zero.
+func (d *Decimal) UnmarshalJSON(p []byte) error {
+	p = bytes.TrimSpace(p)
+	if bytes.Equal(p, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+	if len(p) >= 2 && p[0] == '"' && p[len(p)-1] == '"' {
+		s, err := strconv.Unquote(string(p))
+		if err != nil {
+			return fmt.Errorf("invalid decimal %s", p)
+		}
+		p = []byte(strings.TrimSpace(s))
+		if len(p) == 0 {
+			*d = Decimal{}
+			return nil
+		}
+	}
+
+	parsed, err := ParseDecimal(string(p))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// currencyMinorUnits lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit.
+var currencyMinorUnits = map[string]int32{
+	"BHD": 3, "BIF": 0, "CLF": 4, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3,
+	"ISK": 0, "JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3,
+	"OMR": 3, "PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "UYI": 0, "UYW": 4,
+	"VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// CurrencyMinorUnits returns the number of decimal places of the minor unit
+// of the ISO 4217 currency, e.g. 2 for USD cents and 0 for JPY.
+func CurrencyMinorUnits(currency string) int32 {
+	if units, ok := currencyMinorUnits[strings.ToUpper(currency)]; ok {
+		return units
+	}
+	return 2
+}
+
+// NewDecimalFromMinorUnits returns the amount of units of the minor unit of
+// currency, e.g. 1050 USD cents is 10.50.
+func NewDecimalFromMinorUnits(units int64, currency string) Decimal {
+	return NewDecimal(units, CurrencyMinorUnits(currency))
+}
+
+// RoundToCurrency returns d rounded to the minor unit of currency.
+func (d Decimal) RoundToCurrency(currency string) Decimal {
+	return d.Round(CurrencyMinorUnits(currency))
+}
+
+// MinorUnits returns d as a number of minor units of currency, e.g. 10.50 is
+// 1050 USD cents. ok is false if d has more decimal places than the currency
+// or does not fit in an int64.
+func (d Decimal) MinorUnits(currency string) (units int64, ok bool) {
+	places := CurrencyMinorUnits(currency)
+	if d.Round(places).Cmp(d) != 0 {
+		return 0, false
+	}
+
+	var u *big.Int
+	if places >= d.scale {
+		u = d.rescaled(places)
+	} else {
+		u = new(big.Int).Quo(d.int(), pow10(d.scale-places))
+	}
+	if !u.IsInt64() {
+		return 0, false
+	}
+	return u.Int64(), true
+}
@@ -0,0 +1,161 @@
+package routefusion
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseDecimal(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected string
+		err      bool
+	}{
+		{in: "0", expected: "0"},
+		{in: "123.45", expected: "123.45"},
+		{in: "-0.05", expected: "-0.05"},
+		{in: "+7", expected: "7"},
+		{in: ".5", expected: "0.5"},
+		{in: "5.", expected: "5"},
+		{in: "1.25e2", expected: "125"},
+		{in: "12E-3", expected: "0.012"},
+		{in: "", err: true},
+		{in: ".", err: true},
+		{in: "1.2.3", err: true},
+		{in: "--1", err: true},
+		{in: "1,5", err: true},
+		{in: "1e", err: true},
+		{in: "abc", err: true},
+		{in: "1e100", expected: "1" + strings.Repeat("0", 100)},
+		{in: "1e99999999", err: true},
+		{in: "1e-2147483648", err: true},
+		{in: "0." + strings.Repeat("1", 101), err: true},
+		{in: "1.5e-100", err: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.in, func(t *testing.T) {
+			d, err := ParseDecimal(testCase.in)
+			if testCase.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, d.String())
+		})
+	}
+}
+
+func Test_DecimalArithmetic(t *testing.T) {
+	d := MustParseDecimal
+	testCases := []struct {
+		desc     string
+		result   Decimal
+		expected string
+	}{
+		{desc: "add aligns scales", result: d("0.1").Add(d("0.25")), expected: "0.35"},
+		{desc: "sub", result: d("10").Sub(d("0.01")), expected: "9.99"},
+		{desc: "mul", result: d("1.5").Mul(d("-2.25")), expected: "-3.375"},
+		{desc: "div rounds half up", result: d("2").Div(d("3"), 2), expected: "0.67"},
+		{desc: "div negative", result: d("-1").Div(d("8"), 2), expected: "-0.13"},
+		{desc: "div by scaled divisor", result: d("100").Div(d("0.25"), 0), expected: "400"},
+		{desc: "div negative places", result: d("1000").Div(d("3"), -1), expected: "333"},
+		{desc: "round half away from zero", result: d("-2.345").Round(2), expected: "-2.35"},
+		{desc: "round keeps shorter values", result: d("2.3").Round(2), expected: "2.3"},
+		{desc: "neg", result: d("4.2").Neg(), expected: "-4.2"},
+		{desc: "abs", result: d("-4.2").Abs(), expected: "4.2"},
+		{desc: "zero value", result: Decimal{}.Add(d("1.10")), expected: "1.10"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.result.String())
+		})
+	}
+
+	assert.Panics(t, func() { d("1").Div(Decimal{}, 2) })
+}
+
+func Test_DecimalCompare(t *testing.T) {
+	a, b := MustParseDecimal("1.50"), MustParseDecimal("1.5")
+	assert.True(t, a.Equal(b))
+	assert.Equal(t, 0, a.Cmp(b))
+	assert.True(t, a.LessThan(MustParseDecimal("1.51")))
+	assert.True(t, a.GreaterThan(MustParseDecimal("-2")))
+	assert.True(t, Decimal{}.IsZero())
+	assert.Equal(t, -1, MustParseDecimal("-0.01").Sign())
+	assert.Equal(t, 1.5, a.Float64())
+}
+
+func Test_DecimalStringFixed(t *testing.T) {
+	assert.Equal(t, "10.00", MustParseDecimal("10").StringFixed(2))
+	assert.Equal(t, "0.13", MustParseDecimal("0.125").StringFixed(2))
+	assert.Equal(t, "3", MustParseDecimal("2.5").StringFixed(0))
+}
+
+func Test_DecimalJSON(t *testing.T) {
+	var v struct {
+		Number Decimal  `json:"number"`
+		String Decimal  `json:"string"`
+		Null   Decimal  `json:"null"`
+		Empty  Decimal  `json:"empty"`
+		Ptr    *Decimal `json:"ptr"`
+	}
+	err := json.Unmarshal([]byte(`{"number": 0.1, "string": "1234.5678",
+		"null": null, "empty": "", "ptr": "-3"}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "0.1", v.Number.String())
+	assert.Equal(t, "1234.5678", v.String.String())
+	assert.True(t, v.Null.IsZero())
+	assert.True(t, v.Empty.IsZero())
+	assert.Equal(t, "-3", v.Ptr.String())
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"number":0.1,"string":1234.5678,"null":0,"empty":0,"ptr":-3}`,
+		string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"number": "1.2.3"}`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`{"number": true}`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`{"number": 1e99999999}`), &v))
+}
+
+func Test_DecimalCurrency(t *testing.T) {
+	testCases := []struct {
+		currency      string
+		amount        string
+		expectedRound string
+		expectedUnits int64
+		expectedOK    bool
+	}{
+		{currency: "USD", amount: "10.5", expectedRound: "10.5", expectedUnits: 1050, expectedOK: true},
+		{currency: "usd", amount: "10.005", expectedRound: "10.01"},
+		{currency: "JPY", amount: "1500", expectedRound: "1500", expectedUnits: 1500, expectedOK: true},
+		{currency: "JPY", amount: "1500.5", expectedRound: "1501"},
+		{currency: "KWD", amount: "1.2345", expectedRound: "1.235"},
+		{currency: "KWD", amount: "1.234", expectedRound: "1.234", expectedUnits: 1234, expectedOK: true},
+		{currency: "USD", amount: "100000000000000000000", expectedRound: "100000000000000000000"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.currency+" "+testCase.amount, func(t *testing.T) {
+			d := MustParseDecimal(testCase.amount)
+			assert.Equal(t, testCase.expectedRound, d.RoundToCurrency(testCase.currency).String())
+
+			units, ok := d.MinorUnits(testCase.currency)
+			assert.Equal(t, testCase.expectedOK, ok)
+			assert.Equal(t, testCase.expectedUnits, units)
+		})
+	}
+
+	assert.Equal(t, "10.50", NewDecimalFromMinorUnits(1050, "USD").String())
+	assert.Equal(t, "1050", NewDecimalFromMinorUnits(1050, "JPY").String())
+}
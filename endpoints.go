@@ -0,0 +1,125 @@
+package routefusion
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/routefusion/routefusion-golang/client"
+)
+
+// endpoint describes the name, the HTTP method and the path template of a
+// single Routefusion API operation. Operations that move money require an
+// idempotency key so that retrying them cannot pay out twice.
+type endpoint struct {
+	name                   string
+	method                 string
+	path                   string
+	requiresIdempotencyKey bool
+}
+
+// operation fills in the path template with the escaped args and returns
+// the client.Operation to be sent.
+func (e endpoint) operation(args ...interface{}) client.Operation {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = escapePathArg(arg)
+	}
+	return client.Operation{
+		Name:                   e.name,
+		HTTPMethod:             e.method,
+		HTTPPath:               fmt.Sprintf(e.path, escaped...),
+		RequiresIdempotencyKey: e.requiresIdempotencyKey,
+	}
+}
+
+// escapePathArg escapes arg as a single path segment, so that IDs such as
+// "../balance" cannot point to another endpoint. Dot segments are escaped
+// as well.
+func escapePathArg(arg interface{}) string {
+	s := url.PathEscape(fmt.Sprint(arg))
+	if s == "." || s == ".." {
+		s = strings.Replace(s, ".", "%2E", -1)
+	}
+	return s
+}
+
+// Users
+var (
+	getUserEndpoint         = endpoint{name: "GetUser", method: http.MethodGet, path: "/v1/users/me"}
+	updateUserEndpoint      = endpoint{name: "UpdateUser", method: http.MethodPut, path: "/v1/users/me"}
+	getUserMasterEndpoint   = endpoint{name: "GetUserMaster", method: http.MethodGet, path: "/v1/users/%s"}
+	listUsersMasterEndpoint = endpoint{name: "ListUsersMaster", method: http.MethodGet, path: "/v1/users"}
+)
+
+// Beneficiaries
+var (
+	listBeneficiariesEndpoint              = endpoint{name: "ListBeneficiaries", method: http.MethodGet, path: "/v1/beneficiaries"}
+	getBeneficiaryEndpoint                 = endpoint{name: "GetBeneficiary", method: http.MethodGet, path: "/v1/beneficiaries/%s"}
+	createBeneficiaryEndpoint              = endpoint{name: "CreateBeneficiary", method: http.MethodPost, path: "/v1/beneficiaries"}
+	updateBeneficiaryEndpoint              = endpoint{name: "UpdateBeneficiary", method: http.MethodPut, path: "/v1/beneficiaries/%s"}
+	getSubUserBeneficiariesMasterEndpoint  = endpoint{name: "GetSubUserBeneficiariesMaster", method: http.MethodGet, path: "/v1/users/%s/beneficiaries"}
+	getSubUserBeneficiaryMasterEndpoint    = endpoint{name: "GetSubUserBeneficiaryMaster", method: http.MethodGet, path: "/v1/users/%s/beneficiaries/%s"}
+	createSubUserBeneficiaryMasterEndpoint = endpoint{name: "CreateSubUserBeneficiaryMaster", method: http.MethodPost, path: "/v1/users/%s/beneficiaries"}
+	updateSubUserBeneficiaryMasterEndpoint = endpoint{name: "UpdateSubUserBeneficiaryMaster", method: http.MethodPut, path: "/v1/users/%s/beneficiaries/%s"}
+)
+
+// Quotes
+var (
+	createQuoteEndpoint = endpoint{name: "CreateQuote", method: http.MethodPost, path: "/v1/quotes", requiresIdempotencyKey: true}
+)
+
+// Transfers
+var (
+	createTransferEndpoint          = endpoint{name: "CreateTransfer", method: http.MethodPost, path: "/v1/transfers", requiresIdempotencyKey: true}
+	getTransferEndpoint             = endpoint{name: "GetTransfer", method: http.MethodGet, path: "/v1/transfers/%s"}
+	cancelTransferEndpoint          = endpoint{name: "CancelTransfer", method: http.MethodPost, path: "/v1/transfers/%s/cancel"}
+	createTransferMasterEndpoint    = endpoint{name: "CreateTransferMaster", method: http.MethodPost, path: "/v1/users/%s/transfers", requiresIdempotencyKey: true}
+	getTransferMasterEndpoint       = endpoint{name: "GetTransferMaster", method: http.MethodGet, path: "/v1/users/%s/transfers/%s"}
+	getTransferStatusMasterEndpoint = endpoint{name: "GetTransferStatusMaster", method: http.MethodGet, path: "/v1/users/%s/transfers/%s/status"}
+	cancelTransferMasterEndpoint    = endpoint{name: "CancelTransferMaster", method: http.MethodPost, path: "/v1/users/%s/transfers/%s/cancel"}
+)
+
+// Batch transfers
+var (
+	createBatchPaymentEndpoint = endpoint{name: "CreateBatchPayment", method: http.MethodPost, path: "/v1/batch", requiresIdempotencyKey: true}
+	getBatchPaymentEndpoint    = endpoint{name: "GetBatchPayment", method: http.MethodGet, path: "/v1/batch/%s"}
+)
+
+// Transactions
+var (
+	getTransactionsEndpoint = endpoint{name: "GetTransactions", method: http.MethodGet, path: "/v1/transactions"}
+)
+
+// Account
+var (
+	getBalanceEndpoint = endpoint{name: "GetBalance", method: http.MethodGet, path: "/v1/balance"}
+)
+
+// Webhooks
+var (
+	getWebhookEndpoint    = endpoint{name: "GetWebhook", method: http.MethodGet, path: "/v1/webhooks/%s"}
+	updateWebhookEndpoint = endpoint{name: "UpdateWebhook", method: http.MethodPut, path: "/v1/webhooks/%s"}
+	indexWebhooksEndpoint = endpoint{name: "IndexWebhooks", method: http.MethodGet, path: "/v1/webhooks"}
+	createWebhookEndpoint = endpoint{name: "CreateWebhook", method: http.MethodPost, path: "/v1/webhooks"}
+	deleteWebhookEndpoint = endpoint{name: "DeleteWebhook", method: http.MethodDelete, path: "/v1/webhooks/%s"}
+)
+
+// KYC
+var (
+	createKYCEndpoint = endpoint{name: "CreateKYC", method: http.MethodPost, path: "/v1/users/%s/kyc"}
+	showKYCEndpoint   = endpoint{name: "ShowKYC", method: http.MethodGet, path: "/v1/users/%s/kyc"}
+	updateKYCEndpoint = endpoint{name: "UpdateUserKYC", method: http.MethodPut, path: "/v1/users/%s/kyc"}
+	deleteKYCEndpoint = endpoint{name: "DeleteKYC", method: http.MethodDelete, path: "/v1/users/%s/kyc"}
+)
+
+// Currency coverage
+var (
+	getCurrenciesEndpoint = endpoint{name: "GetCurrencies", method: http.MethodGet, path: "/v1/currencies"}
+)
+
+// Wire instructions
+var (
+	getWireInstructionsEndpoint = endpoint{name: "GetWireInstructions", method: http.MethodGet, path: "/v1/wire-instructions/%s"}
+)
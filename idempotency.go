@@ -0,0 +1,23 @@
+package routefusion
+
+// idempotencyKeyRecorder is implemented by the responses of operations that
+// are sent with an idempotency key, so that callers can reconcile them.
+type idempotencyKeyRecorder interface {
+	recordIdempotencyKey(key string)
+}
+
+func (q *QuoteResponse) recordIdempotencyKey(key string) {
+	q.IdempotencyKey = key
+}
+
+func (t *TransferResponse) recordIdempotencyKey(key string) {
+	t.IdempotencyKey = key
+}
+
+func (t *TransferState) recordIdempotencyKey(key string) {
+	t.IdempotencyKey = key
+}
+
+func (b *BatchTransferStatus) recordIdempotencyKey(key string) {
+	b.IdempotencyKey = key
+}
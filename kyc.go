@@ -0,0 +1,229 @@
+package routefusion
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/routefusion/routefusion-golang/client"
+)
+
+// CreateKYC submits the KYC details of a sub user.
+func (a *API) CreateKYC(subUserID string, kycBody KYCBody) error {
+	return a.CreateKYCWithContext(context.Background(), subUserID, kycBody)
+}
+
+// CreateKYCWithContext is like CreateKYC but binds the request to ctx.
+func (a *API) CreateKYCWithContext(ctx context.Context,
+	subUserID string, kycBody KYCBody) error {
+	return a.do(ctx, createKYCEndpoint.operation(subUserID), kycBody, nil)
+}
+
+// CreateKYCWithDocuments is like CreateKYC but also uploads documents.
+func (a *API) CreateKYCWithDocuments(subUserID string, kycBody KYCBody,
+	documents ...KYCDocument) error {
+	return a.CreateKYCWithDocumentsWithContext(context.Background(), subUserID,
+		kycBody, documents...)
+}
+
+// CreateKYCWithDocumentsWithContext is like CreateKYCWithDocuments but binds
+// the request to ctx.
+func (a *API) CreateKYCWithDocumentsWithContext(ctx context.Context, subUserID string,
+	kycBody KYCBody, documents ...KYCDocument) error {
+	body, err := kycMultipart(kycBody, documents)
+	if err != nil {
+		return err
+	}
+	return a.do(ctx, createKYCEndpoint.operation(subUserID), body, nil)
+}
+
+// ShowKYC returns the KYC details of a sub user.
+func (a *API) ShowKYC(subUserID string) (*KYCDetails, error) {
+	return a.ShowKYCWithContext(context.Background(), subUserID)
+}
+
+// ShowKYCWithContext is like ShowKYC but binds the request to ctx.
+func (a *API) ShowKYCWithContext(ctx context.Context,
+	subUserID string) (*KYCDetails, error) {
+	out := &KYCDetails{}
+	if err := a.do(ctx, showKYCEndpoint.operation(subUserID), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateUserKYC updates the KYC details of a sub user.
+func (a *API) UpdateUserKYC(subUserID string, kycBody KYCBody) error {
+	return a.UpdateUserKYCWithContext(context.Background(), subUserID, kycBody)
+}
+
+// UpdateUserKYCWithContext is like UpdateUserKYC but binds the request to ctx.
+func (a *API) UpdateUserKYCWithContext(ctx context.Context,
+	subUserID string, kycBody KYCBody) error {
+	return a.do(ctx, updateKYCEndpoint.operation(subUserID), kycBody, nil)
+}
+
+// UpdateUserKYCWithDocuments is like UpdateUserKYC but also uploads
+// documents.
+func (a *API) UpdateUserKYCWithDocuments(subUserID string, kycBody KYCBody,
+	documents ...KYCDocument) error {
+	return a.UpdateUserKYCWithDocumentsWithContext(context.Background(), subUserID,
+		kycBody, documents...)
+}
+
+// UpdateUserKYCWithDocumentsWithContext is like UpdateUserKYCWithDocuments but
+// binds the request to ctx.
+func (a *API) UpdateUserKYCWithDocumentsWithContext(ctx context.Context, subUserID string,
+	kycBody KYCBody, documents ...KYCDocument) error {
+	body, err := kycMultipart(kycBody, documents)
+	if err != nil {
+		return err
+	}
+	return a.do(ctx, updateKYCEndpoint.operation(subUserID), body, nil)
+}
+
+// DeleteKYC removes the KYC details of a sub user.
+func (a *API) DeleteKYC(subUserID string) error {
+	return a.DeleteKYCWithContext(context.Background(), subUserID)
+}
+
+// DeleteKYCWithContext is like DeleteKYC but binds the request to ctx.
+func (a *API) DeleteKYCWithContext(ctx context.Context, subUserID string) error {
+	return a.do(ctx, deleteKYCEndpoint.operation(subUserID), nil, nil)
+}
+
+// kycDateLayout is the format of the dates of KYC details.
+const kycDateLayout = "2006-01-02"
+
+// maxOwnership is the share of a company its owners can hold together.
+var maxOwnership = NewDecimalFromInt(100)
+
+// Validate checks b before it is submitted. It returns a *ValidationError
+// listing the missing company details and identity documents of officers
+// and owners, the dates not formatted "2006-01-02", and the ownership
+// percentages that are invalid or sum to more than 100. Officers with Owner
+// set count as owners.
+func (b *KYCBody) Validate() error {
+	v := &validator{}
+	v.require("companyName", b.CompanyName)
+	v.require("country", b.Country)
+	v.check("dateOfIncorporation", b.DateOfIncorporation,
+		validKYCDate(b.DateOfIncorporation), "must be formatted YYYY-MM-DD")
+
+	total := Decimal{}
+	for i := range b.Officers {
+		officer := &b.Officers[i]
+		ownership := officer.validate(v, fmt.Sprintf("officers[%d]", i), officer.Owner)
+		if officer.Owner {
+			total = total.Add(ownership)
+		}
+	}
+	for i := range b.Owners {
+		ownership := b.Owners[i].validate(v, fmt.Sprintf("owners[%d]", i), true)
+		total = total.Add(ownership)
+	}
+	if total.GreaterThan(maxOwnership) {
+		v.fail("owners", fmt.Sprintf("ownership sums to %s%%, more than 100%%", total))
+	}
+	return v.err()
+}
+
+// validate checks the person at path, requiring ownership if owner, and
+// returns the ownership, zero if it is not valid.
+func (p *KYCPerson) validate(v *validator, path string, owner bool) Decimal {
+	v.require(path+".firstName", p.FirstName)
+	v.require(path+".lastName", p.LastName)
+	v.require(path+".idType", p.IDType)
+	v.require(path+".idNumber", p.IDNumber)
+	if v.require(path+".dob", p.Dob) && !validKYCDate(p.Dob) {
+		v.fail(path+".dob", "must be formatted YYYY-MM-DD")
+	}
+
+	if owner && !v.require(path+".ownership", p.Ownership) {
+		return Decimal{}
+	}
+	if p.Ownership == "" {
+		return Decimal{}
+	}
+	ownership, err := ParseDecimal(strings.TrimSuffix(strings.TrimSpace(p.Ownership), "%"))
+	if err != nil || ownership.Sign() < 0 || ownership.GreaterThan(maxOwnership) {
+		v.fail(path+".ownership", "must be a percentage between 0 and 100")
+		return Decimal{}
+	}
+	return ownership
+}
+
+func validKYCDate(s string) bool {
+	_, err := time.Parse(kycDateLayout, s)
+	return err == nil
+}
+
+// KYCBody returns the details as a KYCBody, so that they can be changed and
+// submitted again with UpdateUserKYC.
+func (d *KYCDetails) KYCBody() KYCBody {
+	b := KYCBody(*d)
+	b.Officers = append([]KYCPerson(nil), d.Officers...)
+	b.Owners = append([]KYCPerson(nil), d.Owners...)
+	b.Payments.Countries = append([]string(nil), d.Payments.Countries...)
+	return b
+}
+
+// KYCDocumentKind is the kind of a KYC document.
+type KYCDocumentKind string
+
+// KYC document kinds.
+const (
+	KYCPassport                 KYCDocumentKind = "passport"
+	KYCNationalID               KYCDocumentKind = "national_id"
+	KYCDriversLicense           KYCDocumentKind = "drivers_license"
+	KYCProofOfAddress           KYCDocumentKind = "proof_of_address"
+	KYCIncorporationCertificate KYCDocumentKind = "incorporation_certificate"
+)
+
+// KYCDocument is a file supporting KYC details.
+type KYCDocument struct {
+	Kind     KYCDocumentKind
+	FileName string
+
+	// ContentType is guessed from the extension of FileName if empty.
+	ContentType string
+
+	// Content is read once, when the request is built.
+	Content io.Reader
+}
+
+// kycMultipart encodes the KYC details as a JSON field named "kyc" and every
+// document as a file in a field named after its kind.
+func kycMultipart(kycBody KYCBody, documents []KYCDocument) (*client.Multipart, error) {
+	v := &validator{}
+	for i, d := range documents {
+		path := fmt.Sprintf("documents[%d]", i)
+		v.require(path+".kind", string(d.Kind))
+		v.require(path+".fileName", d.FileName)
+		if d.Content == nil {
+			v.fail(path+".content", "is required")
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	body := client.NewMultipart()
+	if err := body.WriteJSON("kyc", kycBody); err != nil {
+		return nil, err
+	}
+	for _, d := range documents {
+		contentType := d.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(d.FileName))
+		}
+		if err := body.WriteFile(string(d.Kind), d.FileName, contentType, d.Content); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,225 @@
+package routefusion
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/routefusion/routefusion-golang/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKYCPerson(ownership string) KYCPerson {
+	return KYCPerson{FirstName: "Ana", LastName: "Diaz", IDType: "passport",
+		IDNumber: "X123", Dob: "1980-02-29", Ownership: ownership}
+}
+
+func Test_KYCBodyValidate(t *testing.T) {
+	valid := func(set func(b *KYCBody)) KYCBody {
+		b := KYCBody{CompanyName: "ACME", Country: "US", DateOfIncorporation: "2010-06-01",
+			Officers: []KYCPerson{testKYCPerson("")},
+			Owners:   []KYCPerson{testKYCPerson("60"), testKYCPerson("40%")}}
+		set(&b)
+		return b
+	}
+
+	testCases := []struct {
+		desc     string
+		input    KYCBody
+		expected []client.FieldError
+	}{
+		{
+			desc:  "valid",
+			input: valid(func(b *KYCBody) {}),
+		},
+		{
+			desc:  "missing company details",
+			input: KYCBody{},
+			expected: []client.FieldError{
+				{Field: "companyName", Message: "is required"},
+				{Field: "country", Message: "is required"},
+			},
+		},
+		{
+			desc: "missing identity documents",
+			input: valid(func(b *KYCBody) {
+				b.Officers[0].IDType = ""
+				b.Officers[0].IDNumber = " "
+				b.Owners[1].FirstName = ""
+			}),
+			expected: []client.FieldError{
+				{Field: "officers[0].idType", Message: "is required"},
+				{Field: "officers[0].idNumber", Message: "is required"},
+				{Field: "owners[1].firstName", Message: "is required"},
+			},
+		},
+		{
+			desc: "invalid dates",
+			input: valid(func(b *KYCBody) {
+				b.DateOfIncorporation = "06/01/2010"
+				b.Officers[0].Dob = "1981-02-29"
+				b.Owners[0].Dob = ""
+			}),
+			expected: []client.FieldError{
+				{Field: "dateOfIncorporation", Message: "must be formatted YYYY-MM-DD"},
+				{Field: "officers[0].dob", Message: "must be formatted YYYY-MM-DD"},
+				{Field: "owners[0].dob", Message: "is required"},
+			},
+		},
+		{
+			desc: "ownership over 100%",
+			input: valid(func(b *KYCBody) {
+				b.Owners = append(b.Owners, testKYCPerson("0.5"))
+			}),
+			expected: []client.FieldError{
+				{Field: "owners", Message: "ownership sums to 100.5%, more than 100%"},
+			},
+		},
+		{
+			desc: "officers owning shares",
+			input: valid(func(b *KYCBody) {
+				b.Officers[0].Owner = true
+				b.Officers[0].Ownership = "10"
+				b.Officers = append(b.Officers, testKYCPerson(""))
+				b.Officers[1].Owner = true
+			}),
+			expected: []client.FieldError{
+				{Field: "officers[1].ownership", Message: "is required"},
+				{Field: "owners", Message: "ownership sums to 110%, more than 100%"},
+			},
+		},
+		{
+			desc: "invalid ownership",
+			input: valid(func(b *KYCBody) {
+				b.Officers[0].Ownership = "half"
+				b.Owners[0].Ownership = ""
+				b.Owners[1].Ownership = "140"
+			}),
+			expected: []client.FieldError{
+				{Field: "officers[0].ownership", Message: "must be a percentage between 0 and 100"},
+				{Field: "owners[0].ownership", Message: "is required"},
+				{Field: "owners[1].ownership", Message: "must be a percentage between 0 and 100"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			err := testCase.input.Validate()
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.IsType(t, &ValidationError{}, err) {
+				assert.Equal(t, testCase.expected, err.(*ValidationError).Fields)
+			}
+		})
+	}
+}
+
+func Test_KYCDetailsKYCBody(t *testing.T) {
+	details := &KYCDetails{}
+	err := json.Unmarshal([]byte(`{"companyName": "ACME", "owners": [{"firstName": "Ana",
+		"ownership": "100"}], "payments": {"countries": ["MX"], "volume": "10000"}}`), details)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := details.KYCBody()
+	assert.Equal(t, KYCBody{
+		CompanyName: "ACME",
+		Owners:      []KYCPerson{{FirstName: "Ana", Ownership: "100"}},
+		Payments:    KYCPayments{Countries: []string{"MX"}, Volume: "10000"},
+	}, body)
+
+	body.Owners[0].Ownership = "50"
+	body.Payments.Countries[0] = "BR"
+	assert.Equal(t, "100", details.Owners[0].Ownership)
+	assert.Equal(t, "MX", details.Payments.Countries[0])
+}
+
+func Test_KYCWithDocuments(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		call           func(a *API, documents ...KYCDocument) error
+		expectedMethod string
+	}{
+		{
+			desc: "CreateKYCWithDocuments",
+			call: func(a *API, documents ...KYCDocument) error {
+				return a.CreateKYCWithDocuments("sub-1", KYCBody{CompanyName: "ACME"}, documents...)
+			},
+			expectedMethod: http.MethodPost,
+		},
+		{
+			desc: "UpdateUserKYCWithDocuments",
+			call: func(a *API, documents ...KYCDocument) error {
+				return a.UpdateUserKYCWithDocuments("sub-1", KYCBody{CompanyName: "ACME"},
+					documents...)
+			},
+			expectedMethod: http.MethodPut,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, testCase.expectedMethod, r.Method)
+				assert.Equal(t, "/v1/users/sub-1/kyc", r.URL.Path)
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Fatal(err)
+				}
+
+				body := KYCBody{}
+				if err := json.Unmarshal([]byte(r.FormValue("kyc")), &body); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, "ACME", body.CompanyName)
+
+				files := map[string]string{}
+				for kind, headers := range r.MultipartForm.File {
+					for _, h := range headers {
+						f, err := h.Open()
+						if err != nil {
+							t.Fatal(err)
+						}
+						content, _ := ioutil.ReadAll(f)
+						files[kind+"/"+h.Filename+"/"+h.Header.Get("Content-Type")] = string(content)
+					}
+				}
+				assert.Equal(t, map[string]string{
+					"passport/passport.pdf/application/pdf":                     "%PDF",
+					"incorporation_certificate/certificate/image/png":           "PNG",
+					"proof_of_address/bill.unknownext/application/octet-stream": "bill",
+				}, files)
+			})
+			defer ts.Close()
+
+			err := testCase.call(a,
+				KYCDocument{Kind: KYCPassport, FileName: "passport.pdf",
+					Content: strings.NewReader("%PDF")},
+				KYCDocument{Kind: KYCIncorporationCertificate, FileName: "certificate",
+					ContentType: "image/png", Content: strings.NewReader("PNG")},
+				KYCDocument{Kind: KYCProofOfAddress, FileName: "bill.unknownext",
+					Content: strings.NewReader("bill")})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_KYCWithDocumentsValidation(t *testing.T) {
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	})
+	defer ts.Close()
+
+	err := a.CreateKYCWithDocuments("sub-1", KYCBody{}, KYCDocument{FileName: "scan.pdf"})
+	if assert.IsType(t, &ValidationError{}, err) {
+		assert.Equal(t, []client.FieldError{
+			{Field: "documents[0].kind", Message: "is required"},
+			{Field: "documents[0].content", Message: "is required"},
+		}, err.(*ValidationError).Fields)
+	}
+}
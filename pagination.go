@@ -0,0 +1,364 @@
+package routefusion
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/routefusion/routefusion-golang/client"
+)
+
+const (
+	// defaultPageLimit is the page size iterators ask for when ListOptions
+	// does not set one.
+	defaultPageLimit = 100
+
+	// maxListPages is the number of pages an iterator fetches before
+	// giving up with ErrTooManyPages.
+	maxListPages = 10000
+
+	// headerNextCursor carries the cursor of the next page on list
+	// responses of cursor paginated endpoints.
+	headerNextCursor = "X-Next-Cursor"
+)
+
+// ErrTooManyPages is returned by iterators which fetched maxListPages pages
+// without reaching the end of the listing, so that an endpoint which never
+// stops returning full pages cannot keep them looping.
+var ErrTooManyPages = errors.New("routefusion: too many pages")
+
+// ListOptions selects the page returned by list operations. The zero value
+// returns the first page with the API's default page size.
+type ListOptions struct {
+	// Page is the 1-based page number. Ignored when Cursor is set.
+	Page int
+
+	// Limit is the maximum number of items per page.
+	Limit int
+
+	// Cursor is the opaque position returned by the API to continue a
+	// cursor paginated listing, see the Cursor method of the iterators.
+	Cursor string
+}
+
+func (o *ListOptions) params() map[string]string {
+	params := map[string]string{}
+	if o == nil {
+		return params
+	}
+
+	if o.Cursor != "" {
+		params["cursor"] = o.Cursor
+	} else if o.Page > 0 {
+		params["page"] = strconv.Itoa(o.Page)
+	}
+	if o.Limit > 0 {
+		params["limit"] = strconv.Itoa(o.Limit)
+	}
+	return params
+}
+
+// list fetches the page of op selected by opts into out and returns the
+// cursor of the next page, if the API returned one.
+func (a *API) list(ctx context.Context, op client.Operation, opts *ListOptions,
+	out interface{}) (string, error) {
+	req, err := a.send(ctx, op, nil, out, opts.params())
+	if err != nil {
+		return "", err
+	}
+	return req.HTTPResponse.Header.Get(headerNextCursor), nil
+}
+
+// pager holds the iteration state shared by every iterator. fetch loads the
+// page selected by the options and returns the IDs of its items and the next
+// cursor.
+type pager struct {
+	ctx   context.Context
+	opts  ListOptions
+	fetch func(ctx context.Context, opts *ListOptions) ([]string, string, error)
+
+	index    int
+	size     int
+	pages    int
+	maxPages int
+	ids      []string
+	done     bool
+	err      error
+}
+
+func newPager(ctx context.Context, opts *ListOptions,
+	fetch func(ctx context.Context, opts *ListOptions) ([]string, string, error)) pager {
+	p := pager{ctx: ctx, fetch: fetch, maxPages: maxListPages}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.Limit <= 0 {
+		p.opts.Limit = defaultPageLimit
+	}
+	if p.opts.Page <= 0 {
+		p.opts.Page = 1
+	}
+	return p
+}
+
+// next advances to the following item, fetching pages as needed, and
+// reports whether there is one.
+func (p *pager) next() bool {
+	for {
+		if p.err != nil {
+			return false
+		}
+		if p.index+1 < p.size {
+			p.index++
+			return true
+		}
+		if p.done {
+			return false
+		}
+		if p.pages >= p.maxPages {
+			p.err = ErrTooManyPages
+			return false
+		}
+
+		ids, cursor, err := p.fetch(p.ctx, &p.opts)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.pages++
+
+		// An endpoint ignoring the pagination parameters serves the same
+		// page again, which ends the iteration without being repeated.
+		if p.repeats(ids) {
+			p.index, p.size, p.done = -1, 0, true
+			continue
+		}
+		p.index, p.size, p.ids = -1, len(ids), ids
+
+		// An empty page is the last one, as is a page without a next cursor
+		// once the listing is paginated with cursors. Short pages are not,
+		// as the API may serve fewer items than the limit asked for.
+		switch {
+		case len(ids) == 0:
+			p.done = true
+		case cursor != "":
+			p.opts.Cursor = cursor
+		case p.opts.Cursor != "":
+			p.done = true
+		default:
+			p.opts.Page++
+		}
+	}
+}
+
+// repeats reports whether the page of ids starts or ends like the previous
+// page, or only holds items of the previous page. Items without an ID are
+// not compared.
+func (p *pager) repeats(ids []string) bool {
+	if len(ids) == 0 || len(p.ids) == 0 {
+		return false
+	}
+	if ids[0] != "" && ids[0] == p.ids[0] {
+		return true
+	}
+	if last := ids[len(ids)-1]; last != "" && last == p.ids[len(p.ids)-1] {
+		return true
+	}
+
+	previous := make(map[string]bool, len(p.ids))
+	for _, id := range p.ids {
+		previous[id] = true
+	}
+	for _, id := range ids {
+		if id == "" || !previous[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// Cursor returns the cursor of the page following the one being iterated,
+// if the API paginates with cursors. It can be used to resume the iteration
+// later through ListOptions.
+func (p *pager) Cursor() string {
+	if p.done {
+		return ""
+	}
+	return p.opts.Cursor
+}
+
+// UserIterator iterates over sub users, fetching pages lazily.
+type UserIterator struct {
+	pager
+	page []AllUserDetails
+}
+
+// Next advances to the next user and reports whether there is one.
+func (it *UserIterator) Next() bool {
+	return it.next()
+}
+
+// User returns the current user.
+func (it *UserIterator) User() AllUserDetails {
+	return it.page[it.index]
+}
+
+// All collects the remaining users, at most max of them if max is positive.
+func (it *UserIterator) All(max int) ([]AllUserDetails, error) {
+	var all []AllUserDetails
+	for (max <= 0 || len(all) < max) && it.Next() {
+		all = append(all, it.User())
+	}
+	return all, it.Err()
+}
+
+// IterateUsersMaster returns an iterator over all the sub users of the
+// master account, starting at the page selected by opts.
+func (a *API) IterateUsersMaster(ctx context.Context, opts *ListOptions) *UserIterator {
+	it := &UserIterator{}
+	it.pager = newPager(ctx, opts, func(ctx context.Context,
+		opts *ListOptions) ([]string, string, error) {
+		it.page = nil
+		cursor, err := a.list(ctx, listUsersMasterEndpoint.operation(), opts, &it.page)
+		ids := make([]string, len(it.page))
+		for i := range it.page {
+			ids[i] = it.page[i].UUID
+		}
+		return ids, cursor, err
+	})
+	return it
+}
+
+// BeneficiaryIterator iterates over beneficiaries, fetching pages lazily.
+type BeneficiaryIterator struct {
+	pager
+	page []Beneficiary
+}
+
+// Next advances to the next beneficiary and reports whether there is one.
+func (it *BeneficiaryIterator) Next() bool {
+	return it.next()
+}
+
+// Beneficiary returns the current beneficiary.
+func (it *BeneficiaryIterator) Beneficiary() Beneficiary {
+	return it.page[it.index]
+}
+
+// All collects the remaining beneficiaries, at most max of them if max is
+// positive.
+func (it *BeneficiaryIterator) All(max int) ([]Beneficiary, error) {
+	var all []Beneficiary
+	for (max <= 0 || len(all) < max) && it.Next() {
+		all = append(all, it.Beneficiary())
+	}
+	return all, it.Err()
+}
+
+// IterateBeneficiaries returns an iterator over all the beneficiaries of the
+// authenticated user, starting at the page selected by opts.
+func (a *API) IterateBeneficiaries(ctx context.Context, opts *ListOptions) *BeneficiaryIterator {
+	it := &BeneficiaryIterator{}
+	it.pager = newPager(ctx, opts, func(ctx context.Context,
+		opts *ListOptions) ([]string, string, error) {
+		it.page = nil
+		cursor, err := a.list(ctx, listBeneficiariesEndpoint.operation(), opts, &it.page)
+		ids := make([]string, len(it.page))
+		for i := range it.page {
+			ids[i] = it.page[i].UUID
+		}
+		return ids, cursor, err
+	})
+	return it
+}
+
+// TransactionIterator iterates over transactions, fetching pages lazily.
+type TransactionIterator struct {
+	pager
+	page []TransactionResponse
+}
+
+// Next advances to the next transaction and reports whether there is one.
+func (it *TransactionIterator) Next() bool {
+	return it.next()
+}
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() TransactionResponse {
+	return it.page[it.index]
+}
+
+// All collects the remaining transactions, at most max of them if max is
+// positive.
+func (it *TransactionIterator) All(max int) ([]TransactionResponse, error) {
+	var all []TransactionResponse
+	for (max <= 0 || len(all) < max) && it.Next() {
+		all = append(all, it.Transaction())
+	}
+	return all, it.Err()
+}
+
+// IterateTransactions returns an iterator over all the transactions of the
+// authenticated user, starting at the page selected by opts.
+func (a *API) IterateTransactions(ctx context.Context, opts *ListOptions) *TransactionIterator {
+	it := &TransactionIterator{}
+	it.pager = newPager(ctx, opts, func(ctx context.Context,
+		opts *ListOptions) ([]string, string, error) {
+		it.page = nil
+		cursor, err := a.list(ctx, getTransactionsEndpoint.operation(), opts, &it.page)
+		ids := make([]string, len(it.page))
+		for i := range it.page {
+			ids[i] = it.page[i].UUID
+		}
+		return ids, cursor, err
+	})
+	return it
+}
+
+// WebhookIterator iterates over webhooks, fetching pages lazily.
+type WebhookIterator struct {
+	pager
+	page []WebhookResponse
+}
+
+// Next advances to the next webhook and reports whether there is one.
+func (it *WebhookIterator) Next() bool {
+	return it.next()
+}
+
+// Webhook returns the current webhook.
+func (it *WebhookIterator) Webhook() WebhookResponse {
+	return it.page[it.index]
+}
+
+// All collects the remaining webhooks, at most max of them if max is
+// positive.
+func (it *WebhookIterator) All(max int) ([]WebhookResponse, error) {
+	var all []WebhookResponse
+	for (max <= 0 || len(all) < max) && it.Next() {
+		all = append(all, it.Webhook())
+	}
+	return all, it.Err()
+}
+
+// IterateWebhooks returns an iterator over all the registered webhooks,
+// starting at the page selected by opts.
+func (a *API) IterateWebhooks(ctx context.Context, opts *ListOptions) *WebhookIterator {
+	it := &WebhookIterator{}
+	it.pager = newPager(ctx, opts, func(ctx context.Context,
+		opts *ListOptions) ([]string, string, error) {
+		it.page = nil
+		cursor, err := a.list(ctx, indexWebhooksEndpoint.operation(), opts, &it.page)
+		ids := make([]string, len(it.page))
+		for i := range it.page {
+			ids[i] = it.page[i].UUID
+		}
+		return ids, cursor, err
+	})
+	return it
+}
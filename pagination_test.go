@@ -0,0 +1,288 @@
+package routefusion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedHandler serves total beneficiaries page by page, with cursors if
+// useCursor is set, and records the query of every request.
+func pagedHandler(total int, useCursor bool, queries *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.RawQuery)
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			page, _ = strconv.Atoi(cursor)
+		}
+
+		start := (page - 1) * limit
+		end := start + limit
+		if end > total {
+			end = total
+		}
+		if useCursor && end < total {
+			w.Header().Set("X-Next-Cursor", strconv.Itoa(page+1))
+		}
+
+		fmt.Fprint(w, "[")
+		for i := start; i < end; i++ {
+			if i > start {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"uuid": "bene-%d"}`, i)
+		}
+		fmt.Fprint(w, "]")
+	}
+}
+
+func Test_ListOptionsParams(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		opts     *ListOptions
+		expected map[string]string
+	}{
+		{
+			desc:     "nil options",
+			expected: map[string]string{},
+		},
+		{
+			desc:     "page and limit",
+			opts:     &ListOptions{Page: 2, Limit: 10},
+			expected: map[string]string{"page": "2", "limit": "10"},
+		},
+		{
+			desc:     "cursor takes precedence over page",
+			opts:     &ListOptions{Page: 2, Limit: 10, Cursor: "abc"},
+			expected: map[string]string{"cursor": "abc", "limit": "10"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.opts.params())
+		})
+	}
+}
+
+func Test_ListBeneficiariesPage(t *testing.T) {
+	var queries []string
+	a, ts := newTestAPI(pagedHandler(5, false, &queries))
+	defer ts.Close()
+
+	page, err := a.ListBeneficiaries(&ListOptions{Page: 2, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"limit=2&page=2"}, queries)
+	assert.Equal(t, []Beneficiary{
+		{BeneficiaryBase: BeneficiaryBase{UUID: "bene-2"}},
+		{BeneficiaryBase: BeneficiaryBase{UUID: "bene-3"}},
+	}, page)
+}
+
+func Test_IterateBeneficiaries(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		total           int
+		useCursor       bool
+		max             int
+		expectedCount   int
+		expectedQueries []string
+	}{
+		{
+			desc:          "a short page does not end the iteration",
+			total:         5,
+			expectedCount: 5,
+			expectedQueries: []string{"limit=2&page=1", "limit=2&page=2",
+				"limit=2&page=3", "limit=2&page=4"},
+		},
+		{
+			desc:          "an empty page ends the iteration",
+			total:         4,
+			expectedCount: 4,
+			expectedQueries: []string{"limit=2&page=1", "limit=2&page=2",
+				"limit=2&page=3"},
+		},
+		{
+			desc:          "cursors are followed until there is none",
+			total:         5,
+			useCursor:     true,
+			expectedCount: 5,
+			expectedQueries: []string{"limit=2&page=1", "cursor=2&limit=2",
+				"cursor=3&limit=2"},
+		},
+		{
+			desc:            "pages are fetched lazily up to the cap",
+			total:           5,
+			max:             3,
+			expectedCount:   3,
+			expectedQueries: []string{"limit=2&page=1", "limit=2&page=2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var queries []string
+			a, ts := newTestAPI(pagedHandler(testCase.total, testCase.useCursor,
+				&queries))
+			defer ts.Close()
+
+			it := a.IterateBeneficiaries(context.Background(), &ListOptions{Limit: 2})
+			all, err := it.All(testCase.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Len(t, all, testCase.expectedCount)
+			for i, b := range all {
+				assert.Equal(t, fmt.Sprintf("bene-%d", i), b.UUID)
+			}
+			assert.Equal(t, testCase.expectedQueries, queries)
+		})
+	}
+}
+
+func Test_IteratorUnpaginatedEndpoint(t *testing.T) {
+	var queries []string
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`[{"uuid": "hook-1"}, {"uuid": "hook-2"}, {"uuid": "hook-3"}]`))
+	})
+	defer ts.Close()
+
+	it := a.IterateWebhooks(context.Background(), &ListOptions{Limit: 2})
+	var uuids []string
+	for it.Next() {
+		uuids = append(uuids, it.Webhook().UUID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"hook-1", "hook-2", "hook-3"}, uuids)
+	assert.Len(t, queries, 2)
+}
+
+func Test_IteratorCappedPageSize(t *testing.T) {
+	var queries []string
+	paged := pagedHandler(7, false, &queries)
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		// The API serves at most 2 items per page, whatever the limit.
+		q := r.URL.Query()
+		q.Set("limit", "2")
+		r.URL.RawQuery = q.Encode()
+		paged(w, r)
+	})
+	defer ts.Close()
+
+	all, err := a.IterateBeneficiaries(context.Background(), &ListOptions{Limit: 5}).All(0)
+	assert.NoError(t, err)
+	if assert.Len(t, all, 7) {
+		for i, b := range all {
+			assert.Equal(t, fmt.Sprintf("bene-%d", i), b.UUID)
+		}
+	}
+	assert.Len(t, queries, 5)
+}
+
+func Test_IteratorRepeatedPage(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		limit    int
+		pages    []string
+		expected []string
+	}{
+		{
+			desc:     "same page",
+			pages:    []string{`[{"uuid": "hook-1"}, {"uuid": "hook-2"}]`},
+			expected: []string{"hook-1", "hook-2"},
+		},
+		{
+			desc: "same first item",
+			pages: []string{`[{"uuid": "hook-1"}, {"uuid": "hook-2"}]`,
+				`[{"uuid": "hook-1"}, {"uuid": "hook-3"}]`},
+			expected: []string{"hook-1", "hook-2"},
+		},
+		{
+			desc: "same last item",
+			pages: []string{`[{"uuid": "hook-1"}, {"uuid": "hook-2"}]`,
+				`[{"uuid": "hook-3"}, {"uuid": "hook-2"}]`},
+			expected: []string{"hook-1", "hook-2"},
+		},
+		{
+			desc:  "no new items",
+			limit: 3,
+			pages: []string{`[{"uuid": "hook-1"}, {"uuid": "hook-2"}, {"uuid": "hook-3"}]`,
+				`[{"uuid": "hook-2"}, {"uuid": "hook-2"}, {"uuid": "hook-1"}]`},
+			expected: []string{"hook-1", "hook-2", "hook-3"},
+		},
+		{
+			desc: "items without ids",
+			pages: []string{`[{}, {}]`, `[{}, {"uuid": "hook-1"}]`,
+				`[{"uuid": "hook-2"}]`},
+			expected: []string{"", "", "", "hook-1", "hook-2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			requests := 0
+			a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+				page := testCase.pages[len(testCase.pages)-1]
+				if requests < len(testCase.pages) {
+					page = testCase.pages[requests]
+				}
+				requests++
+				w.Write([]byte(page))
+			})
+			defer ts.Close()
+
+			limit := testCase.limit
+			if limit == 0 {
+				limit = 2
+			}
+			it := a.IterateWebhooks(context.Background(), &ListOptions{Limit: limit})
+			var uuids []string
+			for it.Next() {
+				uuids = append(uuids, it.Webhook().UUID)
+			}
+			assert.NoError(t, it.Err())
+			assert.Equal(t, testCase.expected, uuids)
+		})
+	}
+}
+
+func Test_IteratorTooManyPages(t *testing.T) {
+	var queries []string
+	a, ts := newTestAPI(pagedHandler(100, false, &queries))
+	defer ts.Close()
+
+	it := a.IterateBeneficiaries(context.Background(), &ListOptions{Limit: 2})
+	it.maxPages = 3
+	all, err := it.All(0)
+	assert.Equal(t, ErrTooManyPages, err)
+	assert.Len(t, all, 6)
+	assert.Len(t, queries, 3)
+}
+
+func Test_IteratorError(t *testing.T) {
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	defer ts.Close()
+
+	it := a.IterateTransactions(context.Background(), nil)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+
+	users, err := a.IterateUsersMaster(context.Background(), nil).All(0)
+	assert.Nil(t, users)
+	assert.Error(t, err)
+}
@@ -0,0 +1,154 @@
+package routefusion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/routefusion/routefusion-golang/client"
+)
+
+// defaultQuoteSafetyMargin is the time before their expiry quotes stop being
+// used when QuoteManagerConfig.SafetyMargin is not set.
+const defaultQuoteSafetyMargin = 10 * time.Second
+
+// QuoteManagerConfig configures a QuoteManager.
+type QuoteManagerConfig struct {
+	// SafetyMargin is how long before ExpiresAt a quote stops being used, to
+	// leave time for the transfer to reach the API. Defaults to ten seconds.
+	SafetyMargin time.Duration
+
+	// MaxRateDrift is the largest relative change of the rate, e.g. 0.01 for
+	// 1%, accepted when a quote is renewed. Larger changes must be confirmed.
+	// Zero accepts any change.
+	MaxRateDrift Decimal
+
+	// Confirm is asked whether to use a renewed quote whose rate drifted more
+	// than MaxRateDrift from the previous one. Renewed quotes are refused
+	// with a *RateDriftError if it is nil or returns false, see
+	// RateDriftError.
+	Confirm func(previous, current *QuoteResponse) bool
+
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// RateDriftError is returned when a renewed quote is refused because its rate
+// drifted too much from the previous one. The previous quote stays cached as
+// the rate later renewals are compared with, so retrying does not bypass
+// MaxRateDrift: renewals keep being refused until Confirm accepts one or the
+// quote is dropped with Forget.
+type RateDriftError struct {
+	Previous *QuoteResponse
+	Current  *QuoteResponse
+
+	// Drift is the relative change of the rate, e.g. 0.02 for 2%.
+	Drift Decimal
+}
+
+func (e *RateDriftError) Error() string {
+	return fmt.Sprintf("rate of %s/%s drifted by %s%% from %s to %s",
+		e.Current.SourceCurrency, e.Current.DestinationCurrency,
+		e.Drift.Mul(NewDecimalFromInt(100)).StringFixed(2), e.Previous.Rate, e.Current.Rate)
+}
+
+// QuoteManager reuses quotes per currency pair and payment date while they
+// are valid and renews them when they are about to expire. It is safe for
+// concurrent use.
+type QuoteManager struct {
+	client Client
+	config QuoteManagerConfig
+
+	mu     sync.Mutex
+	quotes map[quoteKey]*QuoteResponse
+}
+
+type quoteKey struct {
+	source      string
+	destination string
+	paymentDate string
+}
+
+func newQuoteKey(in *QuoteInput) quoteKey {
+	return quoteKey{
+		source:      strings.ToUpper(in.SourceCurrency),
+		destination: strings.ToUpper(in.DestinationCurrency),
+		paymentDate: in.PaymentDate,
+	}
+}
+
+// NewQuoteManager returns a QuoteManager requesting quotes through client.
+func NewQuoteManager(client Client, config QuoteManagerConfig) *QuoteManager {
+	if config.SafetyMargin <= 0 {
+		config.SafetyMargin = defaultQuoteSafetyMargin
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &QuoteManager{
+		client: client,
+		config: config,
+		quotes: map[quoteKey]*QuoteResponse{},
+	}
+}
+
+// usable reports whether q can still be used to create a transfer.
+func (m *QuoteManager) usable(q *QuoteResponse) bool {
+	return m.config.Now().Before(q.ExpiresAt.Add(-m.config.SafetyMargin))
+}
+
+// Quote returns a usable quote for the currency pair and the payment date of
+// in, from the cache or from a new quote request. Quote requests never use
+// the idempotency key ctx may carry, see client.WithIdempotencyKey, as
+// replaying a quote request would return the same, possibly expired, quote.
+func (m *QuoteManager) Quote(ctx context.Context, in *QuoteInput) (*QuoteResponse, error) {
+	key := newQuoteKey(in)
+	m.mu.Lock()
+	previous := m.quotes[key]
+	m.mu.Unlock()
+	if previous != nil && m.usable(previous) {
+		return previous, nil
+	}
+
+	current, err := m.client.CreateQuoteWithContext(client.WithIdempotencyKey(ctx, ""), in)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && !m.config.MaxRateDrift.IsZero() && !previous.Rate.IsZero() {
+		drift := current.Rate.Sub(previous.Rate).Abs().Div(previous.Rate, 8)
+		if drift.GreaterThan(m.config.MaxRateDrift) &&
+			(m.config.Confirm == nil || !m.config.Confirm(previous, current)) {
+			return nil, &RateDriftError{Previous: previous, Current: current, Drift: drift}
+		}
+	}
+
+	m.mu.Lock()
+	m.quotes[key] = current
+	m.mu.Unlock()
+	return current, nil
+}
+
+// Forget drops the quote cached for the currency pair and the payment date
+// of in, so that the next quote is accepted whatever its rate.
+func (m *QuoteManager) Forget(in *QuoteInput) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.quotes, newQuoteKey(in))
+}
+
+// CreateTransfer creates the transfer described by transfer at the rate of a
+// usable quote for quote, renewing it if needed. transfer is not modified.
+// The idempotency key ctx may carry is only used for the transfer.
+func (m *QuoteManager) CreateTransfer(ctx context.Context, quote *QuoteInput,
+	transfer *TransferInput) (*TransferResponse, error) {
+	q, err := m.Quote(ctx, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	in := *transfer
+	in.QuoteUUID = q.UUID
+	return m.client.CreateTransferWithContext(ctx, &in)
+}
@@ -0,0 +1,269 @@
+package routefusion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/routefusion/routefusion-golang/client"
+	"github.com/stretchr/testify/assert"
+)
+
+var quoteTestStart = time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// scriptedQuotes answers quote requests with the given rates one after the
+// other, each quote expiring a minute after quoteTestStart, and records the
+// transfer requests.
+func scriptedQuotes(rates []string, transfers *[]TransferInput) http.HandlerFunc {
+	var mu sync.Mutex
+	quotes := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/v1/transfers" {
+			var in TransferInput
+			json.NewDecoder(r.Body).Decode(&in)
+			*transfers = append(*transfers, in)
+			fmt.Fprintf(w, `{"uuid": "transfer-%d", "state": "created"}`, len(*transfers))
+			return
+		}
+
+		rate := rates[quotes]
+		quotes++
+		expiresAt := quoteTestStart.Add(time.Duration(quotes) * time.Minute)
+		fmt.Fprintf(w, `{"uuid": "quote-%d", "source_currency": "USD",
+			"destination_currency": "MXN", "rate": %s, "expires_at": %q}`,
+			quotes, rate, expiresAt.Format(time.RFC3339))
+	}
+}
+
+func Test_QuoteManager(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		rates          []string
+		config         QuoteManagerConfig
+		elapsed        time.Duration
+		confirm        bool
+		expectedUUID   string
+		expectedErrMsg string
+	}{
+		{
+			desc:         "cached quote",
+			rates:        []string{"20", "21"},
+			elapsed:      30 * time.Second,
+			expectedUUID: "quote-1",
+		},
+		{
+			desc:         "quote within the safety margin",
+			rates:        []string{"20", "21"},
+			elapsed:      55 * time.Second,
+			expectedUUID: "quote-2",
+		},
+		{
+			desc:         "custom safety margin",
+			rates:        []string{"20", "21"},
+			config:       QuoteManagerConfig{SafetyMargin: time.Second},
+			elapsed:      55 * time.Second,
+			expectedUUID: "quote-1",
+		},
+		{
+			desc:         "drift within the maximum",
+			rates:        []string{"20", "20.1"},
+			config:       QuoteManagerConfig{MaxRateDrift: MustParseDecimal("0.01")},
+			elapsed:      time.Minute,
+			expectedUUID: "quote-2",
+		},
+		{
+			desc:           "drift refused",
+			rates:          []string{"20", "20.5"},
+			config:         QuoteManagerConfig{MaxRateDrift: MustParseDecimal("0.01")},
+			elapsed:        time.Minute,
+			expectedErrMsg: "rate of USD/MXN drifted by 2.50% from 20 to 20.5",
+		},
+		{
+			desc:         "drift confirmed",
+			rates:        []string{"20", "20.5"},
+			config:       QuoteManagerConfig{MaxRateDrift: MustParseDecimal("0.01")},
+			confirm:      true,
+			elapsed:      time.Minute,
+			expectedUUID: "quote-2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var transfers []TransferInput
+			a, ts := newTestAPI(scriptedQuotes(testCase.rates, &transfers))
+			defer ts.Close()
+
+			now := quoteTestStart
+			config := testCase.config
+			config.Now = func() time.Time { return now }
+			config.Confirm = func(previous, current *QuoteResponse) bool {
+				return testCase.confirm
+			}
+			m := NewQuoteManager(a, config)
+
+			in := &QuoteInput{SourceCurrency: "USD", DestinationCurrency: "MXN",
+				PaymentDate: "2020/01/02"}
+			quote, err := m.Quote(context.Background(), in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, "quote-1", quote.UUID)
+
+			now = now.Add(testCase.elapsed)
+			quote, err = m.Quote(context.Background(), in)
+			if testCase.expectedErrMsg != "" {
+				assert.Nil(t, quote)
+				if assert.Error(t, err) {
+					assert.Equal(t, testCase.expectedErrMsg, err.Error())
+					assert.IsType(t, &RateDriftError{}, err)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, testCase.expectedUUID, quote.UUID)
+			}
+		})
+	}
+}
+
+func Test_QuoteManagerKeys(t *testing.T) {
+	var transfers []TransferInput
+	a, ts := newTestAPI(scriptedQuotes([]string{"20", "21", "22"}, &transfers))
+	defer ts.Close()
+
+	m := NewQuoteManager(a, QuoteManagerConfig{
+		Now: func() time.Time { return quoteTestStart },
+	})
+	quote := func(source, destination, date string) string {
+		q, err := m.Quote(context.Background(), &QuoteInput{SourceCurrency: source,
+			DestinationCurrency: destination, PaymentDate: date})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q.UUID
+	}
+
+	assert.Equal(t, "quote-1", quote("USD", "MXN", "2020/01/02"))
+	assert.Equal(t, "quote-1", quote("usd", "mxn", "2020/01/02"))
+	assert.Equal(t, "quote-2", quote("USD", "MXN", "2020/01/03"))
+	assert.Equal(t, "quote-2", quote("USD", "MXN", "2020/01/03"))
+	assert.Equal(t, "quote-3", quote("USD", "EUR", "2020/01/02"))
+}
+
+func Test_QuoteManagerForget(t *testing.T) {
+	var transfers []TransferInput
+	a, ts := newTestAPI(scriptedQuotes([]string{"20", "25"}, &transfers))
+	defer ts.Close()
+
+	now := quoteTestStart
+	m := NewQuoteManager(a, QuoteManagerConfig{
+		MaxRateDrift: MustParseDecimal("0.01"),
+		Now:          func() time.Time { return now },
+	})
+	in := &QuoteInput{SourceCurrency: "USD", DestinationCurrency: "MXN"}
+	_, err := m.Quote(context.Background(), in)
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	m.Forget(in)
+	quote, err := m.Quote(context.Background(), in)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "quote-2", quote.UUID)
+	}
+}
+
+func Test_QuoteManagerRateDrift(t *testing.T) {
+	var transfers []TransferInput
+	a, ts := newTestAPI(scriptedQuotes([]string{"20", "25", "25", "25", "30"}, &transfers))
+	defer ts.Close()
+
+	now := quoteTestStart
+	confirm := false
+	m := NewQuoteManager(a, QuoteManagerConfig{
+		MaxRateDrift: MustParseDecimal("0.01"),
+		Confirm: func(previous, current *QuoteResponse) bool {
+			return confirm
+		},
+		Now: func() time.Time { return now },
+	})
+	in := &QuoteInput{SourceCurrency: "USD", DestinationCurrency: "MXN"}
+	_, err := m.Quote(context.Background(), in)
+	assert.NoError(t, err)
+
+	// Retrying at the drifted rate is still refused.
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		_, err = m.Quote(context.Background(), in)
+		if assert.IsType(t, &RateDriftError{}, err) {
+			assert.Equal(t, "20", err.(*RateDriftError).Previous.Rate.String())
+		}
+	}
+
+	confirm = true
+	quote, err := m.Quote(context.Background(), in)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "quote-4", quote.UUID)
+	}
+
+	// The confirmed quote is the new reference, until it is forgotten.
+	now = now.Add(4 * time.Minute)
+	confirm = false
+	_, err = m.Quote(context.Background(), in)
+	assert.IsType(t, &RateDriftError{}, err)
+}
+
+func Test_QuoteManagerCreateTransfer(t *testing.T) {
+	var transfers []TransferInput
+	a, ts := newTestAPI(scriptedQuotes([]string{"20", "21"}, &transfers))
+	defer ts.Close()
+
+	now := quoteTestStart
+	m := NewQuoteManager(a, QuoteManagerConfig{Now: func() time.Time { return now }})
+	quote := &QuoteInput{SourceCurrency: "USD", DestinationCurrency: "MXN"}
+	in := &TransferInput{BeneficiaryID: 7}
+
+	for _, elapsed := range []time.Duration{0, 10 * time.Second, 50 * time.Second} {
+		now = quoteTestStart.Add(elapsed)
+		_, err := m.CreateTransfer(context.Background(), quote, in)
+		assert.NoError(t, err)
+	}
+
+	assert.Empty(t, in.QuoteUUID)
+	if assert.Len(t, transfers, 3) {
+		assert.Equal(t, "quote-1", transfers[0].QuoteUUID)
+		assert.Equal(t, "quote-1", transfers[1].QuoteUUID)
+		assert.Equal(t, "quote-2", transfers[2].QuoteUUID)
+		assert.Equal(t, 7, transfers[2].BeneficiaryID)
+	}
+}
+
+func Test_QuoteManagerIdempotencyKey(t *testing.T) {
+	keys := map[string]string{}
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		keys[r.URL.Path] = r.Header.Get(client.HeaderKeyIdempotencyKey)
+		if r.URL.Path == "/v1/quotes" {
+			w.Write([]byte(`{"uuid": "quote-1", "expires_at": "2020-01-01T10:01:00Z"}`))
+			return
+		}
+		w.Write([]byte(`{"uuid": "transfer-1"}`))
+	})
+	defer ts.Close()
+
+	m := NewQuoteManager(a, QuoteManagerConfig{Now: func() time.Time { return quoteTestStart }})
+	ctx := client.WithIdempotencyKey(context.Background(), "transfer-key")
+	_, err := m.CreateTransfer(ctx, &QuoteInput{SourceCurrency: "USD", DestinationCurrency: "MXN"},
+		&TransferInput{BeneficiaryID: 7})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "transfer-key", keys["/v1/transfers"])
+	assert.NotEmpty(t, keys["/v1/quotes"])
+	assert.NotEqual(t, "transfer-key", keys["/v1/quotes"])
+}
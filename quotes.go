@@ -0,0 +1,18 @@
+package routefusion
+
+import "context"
+
+// CreateQuote requests a new exchange rate quote.
+func (a *API) CreateQuote(body *QuoteInput) (*QuoteResponse, error) {
+	return a.CreateQuoteWithContext(context.Background(), body)
+}
+
+// CreateQuoteWithContext is like CreateQuote but binds the request to ctx.
+func (a *API) CreateQuoteWithContext(ctx context.Context,
+	body *QuoteInput) (*QuoteResponse, error) {
+	out := &QuoteResponse{}
+	if err := a.do(ctx, createQuoteEndpoint.operation(), body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
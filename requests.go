@@ -3,21 +3,20 @@ package routefusion
 import "time"
 
 // User represents the changeable details pertaining to a user.
-// TODO: QUESTION- Is this a multipart or marshalled http body?
 type User struct {
-	UserName string
-	Password string
+	UserName string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
 	UserData
 }
 
 // UserData is a representation of the base data that is common for all users.
 type UserData struct {
-	FirstName   string
-	LastName    string
-	Email       string
-	PhoneNumber string
-	Country     string
-	CompanyName string
+	FirstName   string `json:"first_name,omitempty"`
+	LastName    string `json:"last_name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Country     string `json:"country,omitempty"`
+	CompanyName string `json:"company_name,omitempty"`
 }
 
 // AdminUpdateableUser is a representation of data updateable by an admin.
@@ -25,150 +24,173 @@ type AdminUpdateableUser struct {
 	UserData
 
 	//Optional
-	PostalCode string
+	PostalCode string `json:"postal_code,omitempty"`
 
 	//Optional
-	City string
+	City string `json:"city,omitempty"`
 
 	//Optional
-	Street string
+	Street string `json:"street,omitempty"`
 }
 
 // BeneficiaryInput is a representation of data accompanying a request to
 // create a beneficiary.
 type BeneficiaryInput struct {
-	Type string
+	Type string `json:"type"`
 
 	// Optional for types that are not Personal.
-	FirstNameOnAccount string
+	FirstNameOnAccount string `json:"first_name_on_account,omitempty"`
 
 	// Optional for types that are not Personal.
-	LastNameOnAccount string
+	LastNameOnAccount string `json:"last_name_on_account,omitempty"`
 
 	// Optional for types that are not business.
-	CompanyName string
-
-	BankCountry       string
-	BankName          string
-	AccountNumber     string
-	Currency          string
-	Address1          string
-	Country           string
-	City              string
-	PostalCode        string
-	RoutingNumber     string
-	SwiftBic          string
-	BsbNumber         string
-	Cpfcnpj           string
-	StateProvince     string
-	PhoneNumber       string
-	BranchName        string
-	BankCity          string
-	BankStateProvince string
-	Clabe             string
-	BankCode          string
-	TaxNumber         string
-	BranchCode        string
+	CompanyName string `json:"company_name,omitempty"`
+
+	BankCountry       string `json:"bank_country"`
+	BankName          string `json:"bank_name"`
+	AccountNumber     string `json:"account_number,omitempty"`
+	Currency          string `json:"currency"`
+	Address1          string `json:"address1,omitempty"`
+	Country           string `json:"country,omitempty"`
+	City              string `json:"city,omitempty"`
+	PostalCode        string `json:"postal_code,omitempty"`
+	RoutingNumber     string `json:"routing_number,omitempty"`
+	SwiftBic          string `json:"swift_bic,omitempty"`
+	BsbNumber         string `json:"bsb_number,omitempty"`
+	Cpfcnpj           string `json:"cpfcnpj,omitempty"`
+	StateProvince     string `json:"state_province,omitempty"`
+	PhoneNumber       string `json:"phone_number,omitempty"`
+	BranchName        string `json:"branch_name,omitempty"`
+	BankCity          string `json:"bank_city,omitempty"`
+	BankStateProvince string `json:"bank_state_province,omitempty"`
+	Clabe             string `json:"clabe,omitempty"`
+	BankCode          string `json:"bank_code,omitempty"`
+	TaxNumber         string `json:"tax_number,omitempty"`
+	BranchCode        string `json:"branch_code,omitempty"`
 }
 
 // UpdateBeneficiaryInput represents a set of alterable fields for a benificiary.
+// Only the fields that are set are sent, the others are left unchanged.
 type UpdateBeneficiaryInput struct {
-	BeneficiaryInput
-	Email          string
-	Address2       string
-	AccountType    string
-	BankCity       string
-	BankAddress1   string
-	BankAddress2   string
-	BankCountry    string
-	BankPostalCode string
+	Type               string `json:"type,omitempty"`
+	FirstNameOnAccount string `json:"first_name_on_account,omitempty"`
+	LastNameOnAccount  string `json:"last_name_on_account,omitempty"`
+	CompanyName        string `json:"company_name,omitempty"`
+	Email              string `json:"email,omitempty"`
+	BankCountry        string `json:"bank_country,omitempty"`
+	BankName           string `json:"bank_name,omitempty"`
+	AccountNumber      string `json:"account_number,omitempty"`
+	AccountType        string `json:"account_type,omitempty"`
+	Currency           string `json:"currency,omitempty"`
+	Address1           string `json:"address1,omitempty"`
+	Address2           string `json:"address2,omitempty"`
+	Country            string `json:"country,omitempty"`
+	City               string `json:"city,omitempty"`
+	PostalCode         string `json:"postal_code,omitempty"`
+	RoutingNumber      string `json:"routing_number,omitempty"`
+	SwiftBic           string `json:"swift_bic,omitempty"`
+	BsbNumber          string `json:"bsb_number,omitempty"`
+	Cpfcnpj            string `json:"cpfcnpj,omitempty"`
+	StateProvince      string `json:"state_province,omitempty"`
+	PhoneNumber        string `json:"phone_number,omitempty"`
+	BranchName         string `json:"branch_name,omitempty"`
+	BankCity           string `json:"bank_city,omitempty"`
+	BankStateProvince  string `json:"bank_state_province,omitempty"`
+	BankAddress1       string `json:"bank_address1,omitempty"`
+	BankAddress2       string `json:"bank_address2,omitempty"`
+	BankPostalCode     string `json:"bank_postal_code,omitempty"`
+	Clabe              string `json:"clabe,omitempty"`
+	BankCode           string `json:"bank_code,omitempty"`
+	TaxNumber          string `json:"tax_number,omitempty"`
+	BranchCode         string `json:"branch_code,omitempty"`
 }
 
 // QuoteInput denotes the input structure to create a quote.
 type QuoteInput struct {
-	SourceAmount        int64
-	SourceCurrency      string
-	DestinationCurrency string
+	SourceAmount        Decimal `json:"source_amount"`
+	SourceCurrency      string  `json:"source_currency"`
+	DestinationCurrency string  `json:"destination_currency"`
 	// Format "YYYY/MM/DD or MM/DD/YYYY"
-	PaymentDate string
+	PaymentDate string `json:"payment_date,omitempty"`
 }
 
-// TransferInput is a representation of possible input to transfers.
+// TransferInput is a representation of possible input to transfers. Either
+// SourceAmount or DestinationAmount is set, the other one being computed from
+// the exchange rate.
 type TransferInput struct {
-	BeneficiaryID     int
-	SourceAmount      int64
-	DestinationAmount int64
-	Reference         string
-	QuoteUUID         string
-	AutoComplete      bool
+	BeneficiaryID     int      `json:"beneficiary_id"`
+	SourceAmount      *Decimal `json:"source_amount,omitempty"`
+	DestinationAmount *Decimal `json:"destination_amount,omitempty"`
+	Reference         string   `json:"reference,omitempty"`
+	QuoteUUID         string   `json:"quote_uuid,omitempty"`
+	AutoComplete      bool     `json:"auto_complete"`
 }
 
 // TransferState represents the current state and date of any transaction.
 type TransferState struct {
-	State     string
-	CreatedAt time.Time
+	State     TransferStatus `json:"state"`
+	CreatedAt time.Time      `json:"created_at"`
+	// IdempotencyKey is the key the request was sent with. It is not part of
+	// the API response and is only set when the request carried one.
+	IdempotencyKey string `json:"-"`
 }
 
 // WebhookUpdateInput represents the required field to update a webhook.
 type WebhookUpdateInput struct {
-	URL    string
-	Type   string
-	rfUUID string
+	URL    string `json:"url"`
+	Type   string `json:"type"`
+	RFUUID string `json:"rfuuid,omitempty"`
 }
 
-// KYCBody is the definition of the input style and details for KYC data.
-type KYCBody struct {
-	AgreedToTerms          bool   `json:"agreedToTerms"`
-	AllowAccountManagement bool   `json:"allowAccountManagement"`
-	Address                string `json:"address"`
-	City                   string `json:"city"`
-	CompanyName            string `json:"companyName"`
-	Country                string `json:"country"`
-	DateOfIncorporation    string `json:"dateOfIncorporation"`
-	Dba                    bool   `json:"dba"`
-	DbaName                string `json:"dbaName"`
-	IncorporationNumber    string `json:"incorporationNumber"`
-	Officers               []struct {
-		Address     string `json:"address"`
-		Citizenship string `json:"citizenship"`
-		City        string `json:"city"`
-		Dob         string `json:"dob"`
-		FirstName   string `json:"firstName"`
-		IDNumber    string `json:"idNumber"`
-		IDType      string `json:"idType"`
-		JobTitle    string `json:"jobTitle"`
-		LastName    string `json:"lastName"`
-		Owner       bool   `json:"owner"`
-		Ownership   string `json:"ownership"`
-		PostalCode  string `json:"postalCode"`
-		State       string `json:"state"`
-		Title       string `json:"title"`
-	} `json:"officers"`
-	Owners []struct {
-		Address     string `json:"address"`
-		Citizenship string `json:"citizenship"`
-		City        string `json:"city"`
-		Dob         string `json:"dob"`
-		FirstName   string `json:"firstName"`
-		IDNumber    string `json:"idNumber"`
-		IDType      string `json:"idType"`
-		JobTitle    string `json:"jobTitle"`
-		LastName    string `json:"lastName"`
-		Owner       bool   `json:"owner"`
-		Ownership   string `json:"ownership"`
-		PostalCode  string `json:"postalCode"`
-		State       string `json:"state"`
-		Title       string `json:"title"`
-	} `json:"owners"`
-	Payments struct {
-		Countries []string `json:"countries"`
-		Frequency string   `json:"frequency"`
-		Purpose   string   `json:"purpose"`
-		Volume    string   `json:"volume"`
-	} `json:"payments"`
-	Phone      string `json:"phone"`
+// KYCPerson is an officer or an owner of a company.
+type KYCPerson struct {
+	Address     string `json:"address"`
+	Citizenship string `json:"citizenship"`
+	City        string `json:"city"`
+
+	// Dob is the date of birth, formatted "2006-01-02".
+	Dob       string `json:"dob"`
+	FirstName string `json:"firstName"`
+	IDNumber  string `json:"idNumber"`
+	IDType    string `json:"idType"`
+	JobTitle  string `json:"jobTitle"`
+	LastName  string `json:"lastName"`
+	Owner     bool   `json:"owner"`
+
+	// Ownership is the percentage of the company owned, e.g. "25" or "12.5%".
+	Ownership  string `json:"ownership"`
 	PostalCode string `json:"postalCode"`
 	State      string `json:"state"`
-	Structure  string `json:"structure"`
-	Website    string `json:"website"`
+	Title      string `json:"title"`
+}
+
+// KYCPayments describes the payments a company expects to make.
+type KYCPayments struct {
+	Countries []string `json:"countries"`
+	Frequency string   `json:"frequency"`
+	Purpose   string   `json:"purpose"`
+	Volume    string   `json:"volume"`
+}
+
+// KYCBody is the definition of the input style and details for KYC data.
+type KYCBody struct {
+	AgreedToTerms          bool        `json:"agreedToTerms"`
+	AllowAccountManagement bool        `json:"allowAccountManagement"`
+	Address                string      `json:"address"`
+	City                   string      `json:"city"`
+	CompanyName            string      `json:"companyName"`
+	Country                string      `json:"country"`
+	DateOfIncorporation    string      `json:"dateOfIncorporation"`
+	Dba                    bool        `json:"dba"`
+	DbaName                string      `json:"dbaName"`
+	IncorporationNumber    string      `json:"incorporationNumber"`
+	Officers               []KYCPerson `json:"officers"`
+	Owners                 []KYCPerson `json:"owners"`
+	Payments               KYCPayments `json:"payments"`
+	Phone                  string      `json:"phone"`
+	PostalCode             string      `json:"postalCode"`
+	State                  string      `json:"state"`
+	Structure              string      `json:"structure"`
+	Website                string      `json:"website"`
 }
@@ -93,64 +93,86 @@ type QuoteResponse struct {
 	UUID                string    `json:"uuid"`
 	SourceCurrency      string    `json:"source_currency"`
 	DestinationCurrency string    `json:"destination_currency"`
-	Rate                string    `json:"rate"` // why not float?
-	InvertedRate        string    `json:"inverted_rate"`
+	Rate                Decimal   `json:"rate"`
+	InvertedRate        Decimal   `json:"inverted_rate"`
 	DateOfPayment       time.Time `json:"date_of_payment"`
 	ExpiresAt           time.Time `json:"expires_at"`
 	CreatedAt           time.Time `json:"created_at"`
+	// IdempotencyKey is the key the request was sent with. It is not part of
+	// the API response and is only set when the request carried one.
+	IdempotencyKey string `json:"-"`
 }
 
 // TransferResponse is the standard response to transfer operations.
 type TransferResponse struct {
-	UserID         int         `json:"user_id"`
-	AccountID      interface{} `json:"account_id"`
-	BeneficiaryID  int         `json:"beneficiary_id"`
-	SourceAmount   string      `json:"source_amount"`
-	ExchangeRate   string      `json:"exchange_rate"`
-	Reference      string      `json:"reference"`
-	Fee            string      `json:"fee"`
-	CurrencyPairs  string      `json:"currency_pairs"`
-	CreatedAt      time.Time   `json:"created_at"`
-	UpdatedAt      interface{} `json:"updated_at"`
-	UUID           string      `json:"uuid"`
-	State          string      `json:"state"`
-	AuthorizingIP  string      `json:"authorizing_ip"`
-	TransferStates []struct {
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"created_at"`
-	} `json:"transfer_states"`
-	SourceCurrency      string `json:"source_currency"`
-	DestinationAmount   string `json:"destination_amount"`
-	DestinationCurrency string `json:"destination_currency"`
-	Deposit             bool   `json:"deposit"`
+	UserID              int             `json:"user_id"`
+	AccountID           interface{}     `json:"account_id"`
+	BeneficiaryID       int             `json:"beneficiary_id"`
+	SourceAmount        Decimal         `json:"source_amount"`
+	ExchangeRate        Decimal         `json:"exchange_rate"`
+	Reference           string          `json:"reference"`
+	Fee                 Decimal         `json:"fee"`
+	CurrencyPairs       string          `json:"currency_pairs"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           interface{}     `json:"updated_at"`
+	UUID                string          `json:"uuid"`
+	State               TransferStatus  `json:"state"`
+	AuthorizingIP       string          `json:"authorizing_ip"`
+	TransferStates      []TransferState `json:"transfer_states"`
+	SourceCurrency      string          `json:"source_currency"`
+	DestinationAmount   Decimal         `json:"destination_amount"`
+	DestinationCurrency string          `json:"destination_currency"`
+	Deposit             bool            `json:"deposit"`
+	// IdempotencyKey is the key the request was sent with. It is not part of
+	// the API response and is only set when the request carried one.
+	IdempotencyKey string `json:"-"`
 }
 
 // BatchTransferStatus is the standard batch transfer status response.
 type BatchTransferStatus struct {
-	UUID      string `json:"uuid"`
-	QuoteUUID string `json:"quote_uuid"`
-	Status    string `json:"status"`
+	UUID      string      `json:"uuid"`
+	QuoteUUID string      `json:"quote_uuid"`
+	Status    string      `json:"status"`
+	Items     []BatchItem `json:"items"`
+	// IdempotencyKey is the key the request was sent with. It is not part of
+	// the API response and is only set when the request carried one.
+	IdempotencyKey string `json:"-"`
+}
+
+// BatchItem is the result of a row of a batch payment.
+type BatchItem struct {
+	// Row is the position of the row in the batch file, starting at 1.
+	Row int `json:"row"`
+
+	// TransferUUID is empty if the row was rejected before a transfer was
+	// created.
+	TransferUUID  string         `json:"transfer_uuid"`
+	BeneficiaryID int            `json:"beneficiary_id"`
+	Amount        Decimal        `json:"amount"`
+	Currency      string         `json:"currency"`
+	Reference     string         `json:"reference"`
+	State         TransferStatus `json:"state"`
+
+	// Error explains why the row was rejected or its transfer failed.
+	Error string `json:"error"`
 }
 
 // TransactionResponse is a representation of data about transactions.
 type TransactionResponse struct {
-	UUID                string      `json:"uuid"`
-	UserID              int         `json:"user_id"`
-	AccountID           interface{} `json:"account_id"`
-	BeneficiaryID       int         `json:"beneficiary_id"`
-	CurrencyPairs       string      `json:"currency_pairs"`
-	SourceCurrency      string      `json:"source_currency"`
-	SourceAmount        string      `json:"source_amount"`
-	DestinationAmount   string      `json:"destination_amount"`
-	DestinationCurrency string      `json:"destination_currency"`
-	ExchangeRate        string      `json:"exchange_rate"`
-	AuthorizingIP       string      `json:"authorizing_ip"`
-	State               string      `json:"state"`
-	TransferStates      []struct {
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"created_at"`
-	} `json:"transfer_states"`
-	CreatedAt time.Time `json:"created_at"`
+	UUID                string          `json:"uuid"`
+	UserID              int             `json:"user_id"`
+	AccountID           interface{}     `json:"account_id"`
+	BeneficiaryID       int             `json:"beneficiary_id"`
+	CurrencyPairs       string          `json:"currency_pairs"`
+	SourceCurrency      string          `json:"source_currency"`
+	SourceAmount        Decimal         `json:"source_amount"`
+	DestinationAmount   Decimal         `json:"destination_amount"`
+	DestinationCurrency string          `json:"destination_currency"`
+	ExchangeRate        Decimal         `json:"exchange_rate"`
+	AuthorizingIP       string          `json:"authorizing_ip"`
+	State               TransferStatus  `json:"state"`
+	TransferStates      []TransferState `json:"transfer_states"`
+	CreatedAt           time.Time       `json:"created_at"`
 }
 
 // WebhookResponse is the representation of response data for webhook based
@@ -169,64 +191,29 @@ type WebhookResponse struct {
 // BalanceResponse is a representation of current balance.
 type BalanceResponse struct {
 	Currency string
-	Balance  float64
+	Balance  Decimal
 }
 
 // KYCDetails is a representation of details retained by KYC.
 type KYCDetails struct {
-	AgreedToTerms          bool   `json:"agreedToTerms"`
-	AllowAccountManagement bool   `json:"allowAccountManagement"`
-	Address                string `json:"address"`
-	City                   string `json:"city"`
-	CompanyName            string `json:"companyName"`
-	Country                string `json:"country"`
-	DateOfIncorporation    string `json:"dateOfIncorporation"`
-	Dba                    bool   `json:"dba"`
-	DbaName                string `json:"dbaName"`
-	IncorporationNumber    string `json:"incorporationNumber"`
-	Officers               []struct {
-		Address     string `json:"address"`
-		Citizenship string `json:"citizenship"`
-		City        string `json:"city"`
-		Dob         string `json:"dob"`
-		FirstName   string `json:"firstName"`
-		IDNumber    string `json:"idNumber"`
-		IDType      string `json:"idType"`
-		JobTitle    string `json:"jobTitle"`
-		LastName    string `json:"lastName"`
-		Owner       bool   `json:"owner"`
-		Ownership   string `json:"ownership"`
-		PostalCode  string `json:"postalCode"`
-		State       string `json:"state"`
-		Title       string `json:"title"`
-	} `json:"officers"`
-	Owners []struct {
-		Address     string `json:"address"`
-		Citizenship string `json:"citizenship"`
-		City        string `json:"city"`
-		Dob         string `json:"dob"`
-		FirstName   string `json:"firstName"`
-		IDNumber    string `json:"idNumber"`
-		IDType      string `json:"idType"`
-		JobTitle    string `json:"jobTitle"`
-		LastName    string `json:"lastName"`
-		Owner       bool   `json:"owner"`
-		Ownership   string `json:"ownership"`
-		PostalCode  string `json:"postalCode"`
-		State       string `json:"state"`
-		Title       string `json:"title"`
-	} `json:"owners"`
-	Payments struct {
-		Countries []string `json:"countries"`
-		Frequency string   `json:"frequency"`
-		Purpose   string   `json:"purpose"`
-		Volume    string   `json:"volume"`
-	} `json:"payments"`
-	Phone      string `json:"phone"`
-	PostalCode string `json:"postalCode"`
-	State      string `json:"state"`
-	Structure  string `json:"structure"`
-	Website    string `json:"website"`
+	AgreedToTerms          bool        `json:"agreedToTerms"`
+	AllowAccountManagement bool        `json:"allowAccountManagement"`
+	Address                string      `json:"address"`
+	City                   string      `json:"city"`
+	CompanyName            string      `json:"companyName"`
+	Country                string      `json:"country"`
+	DateOfIncorporation    string      `json:"dateOfIncorporation"`
+	Dba                    bool        `json:"dba"`
+	DbaName                string      `json:"dbaName"`
+	IncorporationNumber    string      `json:"incorporationNumber"`
+	Officers               []KYCPerson `json:"officers"`
+	Owners                 []KYCPerson `json:"owners"`
+	Payments               KYCPayments `json:"payments"`
+	Phone                  string      `json:"phone"`
+	PostalCode             string      `json:"postalCode"`
+	State                  string      `json:"state"`
+	Structure              string      `json:"structure"`
+	Website                string      `json:"website"`
 }
 
 // PaymentInstructions is a representation of payment instructions.
@@ -234,3 +221,48 @@ type PaymentInstructions struct {
 	Currency            string `json:"Currency"`
 	PaymentInstructions string `json:"PaymentInstructions"`
 }
+
+// CurrenciesResponse is the currency coverage of Routefusion.
+type CurrenciesResponse struct {
+	Currencies []Currency `json:"currencies"`
+}
+
+// Currency is a supported currency.
+type Currency struct {
+	// Code is the ISO 4217 code of the currency.
+	Code string `json:"code"`
+	Name string `json:"name"`
+
+	// Source reports whether transfers can be funded in the currency.
+	Source bool `json:"source"`
+
+	// Destination reports whether transfers can be paid out in the currency.
+	Destination bool `json:"destination"`
+
+	// BankCountries are the countries whose banks can receive payouts in
+	// the currency.
+	BankCountries []BankCountry `json:"bank_countries"`
+}
+
+// BankCountry describes the payouts in a currency to banks of a country.
+type BankCountry struct {
+	// Country is the ISO 3166-1 alpha-2 code of the country.
+	Country string `json:"country"`
+
+	// RequiredFields are the JSON names of the BeneficiaryInput fields that
+	// beneficiaries in this corridor need, e.g. "clabe".
+	RequiredFields []string `json:"required_fields"`
+
+	// CutOff is the time of day after which payouts are made on the next
+	// business day.
+	CutOff CutOff `json:"cut_off"`
+}
+
+// CutOff is a time of day in a time zone.
+type CutOff struct {
+	// Time is formatted "15:04".
+	Time string `json:"time"`
+
+	// TimeZone is an IANA time zone name, UTC if empty.
+	TimeZone string `json:"time_zone"`
+}
@@ -0,0 +1,61 @@
+package routefusion
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/routefusion/routefusion-golang/client"
+)
+
+// ErrNilInput is returned when an operation is given a nil input.
+var ErrNilInput = errors.New("routefusion: nil input")
+
+// API is the default implementation of Client. Every operation is built with
+// client.Client.NewRequest and sent with the retry and authorization logic
+// configured on it.
+type API struct {
+	client *client.Client
+}
+
+// Compile time check that API implements every Routefusion operation.
+var _ Client = (*API)(nil)
+
+// New returns an API that talks to Routefusion using the given configuration.
+func New(config client.Config) *API {
+	return &API{client: client.NewClient(config)}
+}
+
+// Handlers returns the handlers every request of a goes through, to add or
+// remove handlers before making requests.
+func (a *API) Handlers() *client.Handlers {
+	return &a.client.Handlers
+}
+
+// do builds the request for op, sends it bound to ctx and decodes the response
+// into output.
+func (a *API) do(ctx context.Context, op client.Operation, body interface{},
+	output interface{}, params ...map[string]string) error {
+	_, err := a.send(ctx, op, body, output, params...)
+	return err
+}
+
+// send is like do but also returns the request that was sent.
+func (a *API) send(ctx context.Context, op client.Operation, body interface{},
+	output interface{}, params ...map[string]string) (*client.Request, error) {
+	if v := reflect.ValueOf(body); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, ErrNilInput
+	}
+	req, err := a.client.NewRequestWithContext(ctx, op, output, body, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+	if r, ok := output.(idempotencyKeyRecorder); ok {
+		r.recordIdempotencyKey(req.IdempotencyKey())
+	}
+	return req, nil
+}
@@ -0,0 +1,532 @@
+package routefusion
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/routefusion/routefusion-golang/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAPI(handler http.HandlerFunc) (*API, *httptest.Server) {
+	ts := httptest.NewServer(handler)
+
+	return New(client.Config{
+		BaseURL:    ts.URL,
+		Authorizer: &client.BearerTokenAuthorizer{Token: "token"},
+		Retryer:    client.DefaultRetryer{NumMaxRetries: 0},
+	}), ts
+}
+
+func Test_API(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		call           func(a *API) (interface{}, error)
+		response       string
+		expectedMethod string
+		expectedPath   string
+		expectedBody   string
+		expectedOP     interface{}
+
+		expectedIdempotencyKey bool
+	}{
+		{
+			desc:           "GetUser",
+			call:           func(a *API) (interface{}, error) { return a.GetUser() },
+			response:       `{"uuid": "user-1"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users/me",
+			expectedOP:     &UserDetails{UUID: "user-1"},
+		},
+		{
+			desc: "UpdateUser",
+			call: func(a *API) (interface{}, error) {
+				return a.UpdateUser(&User{UserName: "jdoe"})
+			},
+			response:       `{"uuid": "user-1", "master_user_uuid": "master-1"}`,
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/v1/users/me",
+			expectedBody:   `"username":"jdoe"`,
+			expectedOP: &UpdatedUserDetails{UserDetails: UserDetails{UUID: "user-1"},
+				MasterUserUUID: "master-1"},
+		},
+		{
+			desc:           "GetUserMaster",
+			call:           func(a *API) (interface{}, error) { return a.GetUserMaster("sub-1") },
+			response:       `{"uuid": "sub-1", "city": "Austin"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users/sub-1",
+			expectedOP:     &AllUserDetails{UserDetails: UserDetails{UUID: "sub-1"}, City: "Austin"},
+		},
+		{
+			desc:           "ListUsersMaster",
+			call:           func(a *API) (interface{}, error) { return a.ListUsersMaster(nil) },
+			response:       `[{"uuid": "sub-1"}]`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users",
+			expectedOP:     []AllUserDetails{{UserDetails: UserDetails{UUID: "sub-1"}}},
+		},
+		{
+			desc:           "ListBeneficiaries",
+			call:           func(a *API) (interface{}, error) { return a.ListBeneficiaries(nil) },
+			response:       `[{"uuid": "bene-1", "status": "verified"}]`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/beneficiaries",
+			expectedOP: []Beneficiary{{BeneficiaryBase: BeneficiaryBase{UUID: "bene-1"},
+				Status: "verified"}},
+		},
+		{
+			desc:           "GetBeneficiary",
+			call:           func(a *API) (interface{}, error) { return a.GetBeneficiary("bene-1") },
+			response:       `{"uuid": "bene-1"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/beneficiaries/bene-1",
+			expectedOP:     &BeneficiaryBase{UUID: "bene-1"},
+		},
+		{
+			desc: "CreateBeneficiary",
+			call: func(a *API) (interface{}, error) {
+				return a.CreateBeneficiary(&BeneficiaryInput{Type: "personal"})
+			},
+			response:       `{"uuid": "bene-1", "type": "personal"}`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/beneficiaries",
+			expectedBody:   `"type":"personal"`,
+			expectedOP:     &BeneficiaryBase{UUID: "bene-1", Type: "personal"},
+		},
+		{
+			desc: "UpdateBeneficiary",
+			call: func(a *API) (interface{}, error) {
+				return a.UpdateBeneficiary("bene-1", &UpdateBeneficiaryInput{Email: "a@b.c"})
+			},
+			response:       `{"uuid": "bene-1", "email": "a@b.c"}`,
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/v1/beneficiaries/bene-1",
+			expectedBody:   `{"email":"a@b.c"}`,
+			expectedOP:     &BeneficiaryBase{UUID: "bene-1", Email: "a@b.c"},
+		},
+		{
+			desc: "GetSubUserBeneficiariesMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.GetSubUserBeneficiariesMaster("sub-1")
+			},
+			response:       `[{"uuid": "bene-1"}]`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users/sub-1/beneficiaries",
+			expectedOP:     []Beneficiary{{BeneficiaryBase: BeneficiaryBase{UUID: "bene-1"}}},
+		},
+		{
+			desc: "GetSubUserBeneficiaryMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.GetSubUserBeneficiaryMaster("sub-1", "bene-1")
+			},
+			response:       `{"uuid": "bene-1"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users/sub-1/beneficiaries/bene-1",
+			expectedOP:     &BeneficiaryBase{UUID: "bene-1"},
+		},
+		{
+			desc: "CreateSubUserBeneficiaryMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.CreateSubUserBeneficiaryMaster("sub-1", &BeneficiaryInput{Currency: "MXN"})
+			},
+			response:       `{"uuid": "bene-1", "currency": "MXN"}`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/users/sub-1/beneficiaries",
+			expectedBody:   `"currency":"MXN"`,
+			expectedOP:     &BeneficiaryBase{UUID: "bene-1", Currency: "MXN"},
+		},
+		{
+			desc: "UpdateSubUserBeneficiaryMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.UpdateSubUserBeneficiaryMaster("sub-1", "bene-1",
+					&UpdateBeneficiaryInput{AccountType: "checking"})
+			},
+			response:       `{"uuid": "bene-1", "account_type": "checking"}`,
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/v1/users/sub-1/beneficiaries/bene-1",
+			expectedBody:   `{"account_type":"checking"}`,
+			expectedOP:     &BeneficiaryBase{UUID: "bene-1", AccountType: "checking"},
+		},
+		{
+			desc: "CreateQuote",
+			call: func(a *API) (interface{}, error) {
+				return a.CreateQuote(&QuoteInput{
+					SourceAmount:   MustParseDecimal("100.50"),
+					SourceCurrency: "USD",
+				})
+			},
+			response:       `{"uuid": "quote-1", "source_currency": "USD", "rate": "18.1234"}`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/quotes",
+			expectedBody:   `"source_amount":100.50,"source_currency":"USD"`,
+			expectedOP: &QuoteResponse{UUID: "quote-1", SourceCurrency: "USD",
+				Rate: MustParseDecimal("18.1234")},
+			expectedIdempotencyKey: true,
+		},
+		{
+			desc: "CreateTransfer",
+			call: func(a *API) (interface{}, error) {
+				amount := MustParseDecimal("250")
+				return a.CreateTransfer(&TransferInput{SourceAmount: &amount,
+					QuoteUUID: "quote-1"})
+			},
+			response: `{"uuid": "transfer-1", "state": "created",
+				"source_amount": "250.00", "fee": 1.5}`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/transfers",
+			expectedBody:   `"source_amount":250,"quote_uuid":"quote-1"`,
+			expectedOP: &TransferResponse{UUID: "transfer-1", State: "created",
+				SourceAmount: MustParseDecimal("250.00"), Fee: MustParseDecimal("1.5")},
+			expectedIdempotencyKey: true,
+		},
+		{
+			desc:           "GetTransfer",
+			call:           func(a *API) (interface{}, error) { return a.GetTransfer("transfer-1") },
+			response:       `{"uuid": "transfer-1"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/transfers/transfer-1",
+			expectedOP:     &TransferResponse{UUID: "transfer-1"},
+		},
+		{
+			desc:           "CancelTransfer",
+			call:           func(a *API) (interface{}, error) { return a.CancelTransfer("transfer-1") },
+			response:       `{"uuid": "transfer-1"}`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/transfers/transfer-1/cancel",
+			expectedOP:     "transfer-1",
+		},
+		{
+			desc: "CreateTransferMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.CreateTransferMaster("sub-1", &TransferInput{Reference: "invoice"})
+			},
+			response:               `{"state": "created"}`,
+			expectedMethod:         http.MethodPost,
+			expectedPath:           "/v1/users/sub-1/transfers",
+			expectedBody:           `"reference":"invoice"`,
+			expectedOP:             &TransferState{State: "created"},
+			expectedIdempotencyKey: true,
+		},
+		{
+			desc: "GetTransferMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.GetTransferMaster("sub-1", "transfer-1")
+			},
+			response:       `{"uuid": "transfer-1"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users/sub-1/transfers/transfer-1",
+			expectedOP:     &TransferResponse{UUID: "transfer-1"},
+		},
+		{
+			desc: "GetTransferStatusMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.GetTransferStatusMaster("sub-1", "transfer-1")
+			},
+			response:       `{"state": "completed"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users/sub-1/transfers/transfer-1/status",
+			expectedOP:     &TransferState{State: "completed"},
+		},
+		{
+			desc: "CancelTransferMaster",
+			call: func(a *API) (interface{}, error) {
+				return a.CancelTransferMaster("sub-1", "transfer-1")
+			},
+			response:       `{"uuid": "transfer-1"}`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/users/sub-1/transfers/transfer-1/cancel",
+			expectedOP:     "transfer-1",
+		},
+		{
+			desc: "CreateBatchPayment",
+			call: func(a *API) (interface{}, error) {
+				return a.CreateBatchPayment(strings.NewReader("beneficiary_id,amount"))
+			},
+			response:               `{"uuid": "batch-1", "status": "created"}`,
+			expectedMethod:         http.MethodPost,
+			expectedPath:           "/v1/batch",
+			expectedBody:           "beneficiary_id,amount",
+			expectedOP:             &BatchTransferStatus{UUID: "batch-1", Status: "created"},
+			expectedIdempotencyKey: true,
+		},
+		{
+			desc:           "GetBatchPayment",
+			call:           func(a *API) (interface{}, error) { return a.GetBatchPayment("batch-1") },
+			response:       `{"uuid": "batch-1"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/batch/batch-1",
+			expectedOP:     &BatchTransferStatus{UUID: "batch-1"},
+		},
+		{
+			desc:           "GetTransactions",
+			call:           func(a *API) (interface{}, error) { return a.GetTransactions(nil) },
+			response:       `[{"uuid": "transaction-1"}]`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/transactions",
+			expectedOP:     []TransactionResponse{{UUID: "transaction-1"}},
+		},
+		{
+			desc:           "GetBalance",
+			call:           func(a *API) (interface{}, error) { return a.GetBalance() },
+			response:       `{"currency": "USD", "balance": 10.5}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/balance",
+			expectedOP:     &BalanceResponse{Currency: "USD", Balance: MustParseDecimal("10.5")},
+		},
+		{
+			desc:           "GetWebhook",
+			call:           func(a *API) (interface{}, error) { return a.GetWebhook("hook-1") },
+			response:       `{"uuid": "hook-1"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/webhooks/hook-1",
+			expectedOP:     &WebhookResponse{UUID: "hook-1"},
+		},
+		{
+			desc: "UpdateWebhook",
+			call: func(a *API) (interface{}, error) {
+				return a.UpdateWebhook("hook-1", WebhookUpdateInput{URL: "https://example.com"})
+			},
+			response:       `{"uuid": "hook-1", "url": "https://example.com"}`,
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/v1/webhooks/hook-1",
+			expectedBody:   `"url":"https://example.com"`,
+			expectedOP:     &WebhookResponse{UUID: "hook-1", URL: "https://example.com"},
+		},
+		{
+			desc:           "IndexWebhooks",
+			call:           func(a *API) (interface{}, error) { return a.IndexWebhooks(nil) },
+			response:       `[{"uuid": "hook-1"}]`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/webhooks",
+			expectedOP:     []WebhookResponse{{UUID: "hook-1"}},
+		},
+		{
+			desc: "CreateWebhook",
+			call: func(a *API) (interface{}, error) {
+				return a.CreateWebhook(WebhookUpdateInput{Type: "transfer"})
+			},
+			response:       `{"uuid": "hook-1", "type": "transfer"}`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/webhooks",
+			expectedBody:   `"type":"transfer"`,
+			expectedOP:     &WebhookResponse{UUID: "hook-1", Type: "transfer"},
+		},
+		{
+			desc:           "DeleteWebhook",
+			call:           func(a *API) (interface{}, error) { return nil, a.DeleteWebhook("hook-1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/v1/webhooks/hook-1",
+		},
+		{
+			desc: "CreateKYC",
+			call: func(a *API) (interface{}, error) {
+				return nil, a.CreateKYC("sub-1", KYCBody{CompanyName: "ACME"})
+			},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/v1/users/sub-1/kyc",
+			expectedBody:   `"companyName":"ACME"`,
+		},
+		{
+			desc:           "ShowKYC",
+			call:           func(a *API) (interface{}, error) { return a.ShowKYC("sub-1") },
+			response:       `{"companyName": "ACME"}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/users/sub-1/kyc",
+			expectedOP:     &KYCDetails{CompanyName: "ACME"},
+		},
+		{
+			desc: "UpdateUserKYC",
+			call: func(a *API) (interface{}, error) {
+				return nil, a.UpdateUserKYC("sub-1", KYCBody{Website: "acme.com"})
+			},
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/v1/users/sub-1/kyc",
+			expectedBody:   `"website":"acme.com"`,
+		},
+		{
+			desc:           "DeleteKYC",
+			call:           func(a *API) (interface{}, error) { return nil, a.DeleteKYC("sub-1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/v1/users/sub-1/kyc",
+		},
+		{
+			desc: "GetCurrencies",
+			call: func(a *API) (interface{}, error) { return a.GetCurrencies() },
+			response: `{"currencies": [{"code": "MXN", "name": "Mexican Peso",
+				"destination": true, "bank_countries": [{"country": "MX",
+				"required_fields": ["clabe"], "cut_off": {"time": "14:00",
+				"time_zone": "America/Mexico_City"}}]}]}`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/currencies",
+			expectedOP: &CurrenciesResponse{Currencies: []Currency{{
+				Code:        "MXN",
+				Name:        "Mexican Peso",
+				Destination: true,
+				BankCountries: []BankCountry{{
+					Country:        "MX",
+					RequiredFields: []string{"clabe"},
+					CutOff:         CutOff{Time: "14:00", TimeZone: "America/Mexico_City"},
+				}},
+			}}},
+		},
+		{
+			desc: "GetWireInstructions",
+			call: func(a *API) (interface{}, error) {
+				return a.GetWireInstructions("USD")
+			},
+			response:       `[{"Currency": "USD", "PaymentInstructions": "wire to"}]`,
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/v1/wire-instructions/USD",
+			expectedOP:     []PaymentInstructions{{Currency: "USD", PaymentInstructions: "wire to"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var idempotencyKey string
+			a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, testCase.expectedMethod, r.Method)
+				assert.Equal(t, testCase.expectedPath, r.URL.Path)
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+				bdy, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Contains(t, string(bdy), testCase.expectedBody)
+
+				idempotencyKey = r.Header.Get(client.HeaderKeyIdempotencyKey)
+				w.Write([]byte(testCase.response))
+			})
+			defer ts.Close()
+
+			actualOP, err := testCase.call(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if testCase.expectedIdempotencyKey {
+				assert.NotEmpty(t, idempotencyKey)
+				testCase.expectedOP.(idempotencyKeyRecorder).recordIdempotencyKey(idempotencyKey)
+			} else {
+				assert.Empty(t, idempotencyKey)
+			}
+			if testCase.expectedOP != nil {
+				assert.Equal(t, testCase.expectedOP, actualOP)
+			}
+		})
+	}
+}
+
+func Test_APIRequestFailure(t *testing.T) {
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer ts.Close()
+
+	out, err := a.GetTransfer("missing")
+	assert.Nil(t, out)
+	rerr, ok := err.(client.RequestFailureError)
+	if !ok {
+		t.Fatalf("expected a client.RequestFailureError, actual: %T", err)
+	}
+	assert.Equal(t, client.ErrCodeNotFound, rerr.Code())
+	assert.Equal(t, http.StatusNotFound, rerr.StatusCode())
+}
+
+func Test_APIWithContextCanceled(t *testing.T) {
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request is expected for a cancelled context")
+	})
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out, err := a.CreateTransferWithContext(ctx, &TransferInput{QuoteUUID: "quote-1"})
+	assert.Nil(t, out)
+	rerr, ok := err.(client.RequestFailureError)
+	if !ok {
+		t.Fatalf("expected a client.RequestFailureError, actual: %T", err)
+	}
+	assert.Equal(t, client.ErrCodeRequestCanceled, rerr.Code())
+}
+
+func Test_APIIdempotencyKey(t *testing.T) {
+	var keys []string
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(client.HeaderKeyIdempotencyKey))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"uuid": "transfer-1"}`))
+	})
+	defer ts.Close()
+	a.client.Retryer = client.DefaultRetryer{NumMaxRetries: 1}
+
+	t.Run("caller supplied key is sent on every attempt", func(t *testing.T) {
+		keys = nil
+		ctx := client.WithIdempotencyKey(context.Background(), "key-1")
+		out, err := a.CreateTransferWithContext(ctx, &TransferInput{QuoteUUID: "quote-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"key-1", "key-1"}, keys)
+		assert.Equal(t, "key-1", out.IdempotencyKey)
+	})
+
+	t.Run("generated key is sent on every attempt", func(t *testing.T) {
+		keys = nil
+		out, err := a.CreateTransfer(&TransferInput{QuoteUUID: "quote-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, keys, 2)
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1])
+		assert.Equal(t, keys[0], out.IdempotencyKey)
+	})
+}
+
+func Test_APIEscapesPathArguments(t *testing.T) {
+	testCases := []struct {
+		id           string
+		expectedPath string
+	}{
+		{id: "bene-1", expectedPath: "/v1/beneficiaries/bene-1"},
+		{id: "../balance", expectedPath: "/v1/beneficiaries/..%2Fbalance"},
+		{id: "..", expectedPath: "/v1/beneficiaries/%2E%2E"},
+		{id: "a b?c#d", expectedPath: "/v1/beneficiaries/a%20b%3Fc%23d"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.id, func(t *testing.T) {
+			var path string
+			a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.EscapedPath()
+				w.Write([]byte(`{"uuid": "bene-1"}`))
+			})
+			defer ts.Close()
+
+			_, err := a.GetBeneficiary(testCase.id)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedPath, path)
+		})
+	}
+}
+
+func Test_APINilInput(t *testing.T) {
+	a, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request is expected for a nil input")
+	})
+	defer ts.Close()
+
+	out, err := a.CreateBeneficiary(nil)
+	assert.Nil(t, out)
+	assert.Equal(t, ErrNilInput, err)
+}
@@ -0,0 +1,91 @@
+package routefusiontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/routefusion/routefusion-golang/webhook"
+)
+
+const webhookDeliveryTimeout = 5 * time.Second
+
+// event is a signed webhook event waiting to be delivered to the webhooks
+// registered when it happened.
+type event struct {
+	payload []byte
+	targets []*webhookTarget
+}
+
+type webhookTarget struct {
+	uuid string
+	url  string
+}
+
+// queueEvent records an event for the webhooks subscribed to its type, or
+// to every type. Events are delivered by the caller once the server lock is
+// released, see deliver. It must be called with the server lock held.
+func (s *Server) queueEvent(t webhook.EventType, data interface{}) {
+	var targets []*webhookTarget
+	for _, w := range s.webhooks {
+		if w.Type == "" || w.Type == string(t) {
+			targets = append(targets, &webhookTarget{uuid: w.UUID, url: w.URL})
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	raw, _ := json.Marshal(data)
+	payload, _ := json.Marshal(webhook.Event{
+		ID:        s.newID("evt"),
+		Type:      t,
+		CreatedAt: s.now(),
+		Data:      raw,
+	})
+	s.events = append(s.events, event{payload: payload, targets: targets})
+}
+
+// takeEvents returns the queued events and empties the queue. It must be
+// called with the server lock held.
+func (s *Server) takeEvents() []event {
+	events := s.events
+	s.events = nil
+	return events
+}
+
+// deliver posts the events to their webhooks and counts the failed
+// deliveries. It must be called without the server lock held, as the
+// webhooks may call the server back.
+func (s *Server) deliver(events []event) {
+	c := &http.Client{Timeout: webhookDeliveryTimeout}
+	for _, e := range events {
+		for _, target := range e.targets {
+			if !s.post(c, target.url, e.payload) {
+				s.mu.Lock()
+				if w := s.findWebhook(target.uuid); w != nil {
+					w.FailedCount++
+				}
+				s.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (s *Server) post(c *http.Client, url string, payload []byte) bool {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderSignature,
+		webhook.Sign([]byte(s.config.WebhookSecret), payload, s.config.Now()))
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < http.StatusMultipleChoices
+}
@@ -0,0 +1,149 @@
+package routefusiontest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault is a failure injected into the responses of a Server.
+type Fault struct {
+	// Method restricts the fault to requests with this method. Any method
+	// matches if empty.
+	Method string
+
+	// Path restricts the fault to requests with this path, or to paths
+	// starting with it if it ends with "*". Any path matches if empty.
+	Path string
+
+	// Times is the number of requests the fault applies to. It applies to
+	// every matching request if zero.
+	Times int
+
+	// Latency delays the response, or the failure, by this duration.
+	Latency time.Duration
+
+	// Status, when set, is answered instead of running the request, with an
+	// API error body of the given Code.
+	Status int
+	Code   string
+
+	// RetryAfter is sent as the Retry-After header of the failure.
+	RetryAfter time.Duration
+
+	// Disconnect closes the connection without answering, as a transport
+	// failure.
+	Disconnect bool
+}
+
+// Inject registers a fault. Faults are matched in the order they were
+// injected, the first matching one applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all the injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// FailNext answers the next n requests with status.
+func (s *Server) FailNext(n, status int) {
+	s.Inject(Fault{Times: n, Status: status})
+}
+
+// RateLimitNext answers the next n requests with 429 Too Many Requests and the
+// given Retry-After.
+func (s *Server) RateLimitNext(n int, retryAfter time.Duration) {
+	s.Inject(Fault{Times: n, Status: http.StatusTooManyRequests,
+		Code: "rate_limited", RetryAfter: retryAfter})
+}
+
+// SetLatency delays every response by d, on top of the latency of the
+// faults. It replaces the latency set before; zero removes it.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+func (f *Fault) matches(method, path string) bool {
+	if f.Method != "" && f.Method != method {
+		return false
+	}
+	if strings.HasSuffix(f.Path, "*") {
+		return strings.HasPrefix(path, strings.TrimSuffix(f.Path, "*"))
+	}
+	return f.Path == "" || f.Path == path
+}
+
+// takeFault returns the fault applying to the request, if any, and uses it
+// up. It must be called with the server lock held.
+func (s *Server) takeFault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(method, path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// apply delays the response and writes the failure of the fault, and reports
+// whether the request was answered.
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if !delay(r, f.Latency) {
+		return true
+	}
+
+	if f.Disconnect {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return true
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	if f.Status == 0 {
+		return false
+	}
+	if f.RetryAfter > 0 {
+		seconds := int((f.RetryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+	code := f.Code
+	if code == "" {
+		code = "injected_fault"
+	}
+	writeJSON(w, f.Status, newAPIError(f.Status, code, http.StatusText(f.Status)))
+	return true
+}
+
+// delay waits for d and reports whether the request is still to be
+// answered, i.e. whether its context is not done.
+func delay(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,436 @@
+package routefusiontest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/routefusion/routefusion-golang"
+	"github.com/routefusion/routefusion-golang/webhook"
+)
+
+// rateKey is the key of the exchange rate from source to destination.
+func rateKey(source, destination string) string {
+	return strings.ToUpper(source) + "/" + strings.ToUpper(destination)
+}
+
+// SetRate sets the exchange rate quoted from source to destination. Unset
+// rates are 1.
+func (s *Server) SetRate(source, destination string, rate routefusion.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates[rateKey(source, destination)] = rate
+}
+
+func (s *Server) rate(source, destination string) routefusion.Decimal {
+	if rate, ok := s.rates[rateKey(source, destination)]; ok {
+		return rate
+	}
+	return routefusion.NewDecimalFromInt(1)
+}
+
+// SetBalance sets the balance of the account, in the account currency.
+func (s *Server) SetBalance(amount routefusion.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = amount
+}
+
+// Balance returns the balance of the account, in the account currency.
+func (s *Server) Balance() routefusion.Decimal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balance
+}
+
+// AddSubUser adds a sub user to the master account and returns it with its
+// UUID.
+func (s *Server) AddSubUser(user routefusion.AllUserDetails) routefusion.AllUserDetails {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user.UUID = s.newID("user")
+	user.CreatedAt, user.UpdatedAt = s.now(), s.now()
+	user.MasterUser = false
+	s.users = append(s.users, &user)
+	return user
+}
+
+// user returns the sub user with the given UUID, or the master user when
+// the route is not scoped to a sub user, as it is if it has more than n path
+// parameters.
+func (s *Server) user(r *request, n int) (*routefusion.AllUserDetails, []string, *apiError) {
+	if len(r.params) <= n {
+		return s.master, r.params, nil
+	}
+	for _, u := range s.users {
+		if u.UUID == r.params[0] {
+			return u, r.params[1:], nil
+		}
+	}
+	return nil, nil, newAPIError(http.StatusNotFound, "not_found", "user not found")
+}
+
+func (s *Server) getUser(r *request) (int, interface{}) {
+	return http.StatusOK, s.master.UserDetails
+}
+
+func (s *Server) updateUser(r *request) (int, interface{}) {
+	in := routefusion.User{}
+	if err := r.decode(&in); err != nil {
+		return err.response()
+	}
+
+	u := &s.master.UserDetails
+	setString(&u.FirstName, in.FirstName)
+	setString(&u.LastName, in.LastName)
+	setString(&u.Email, in.Email)
+	setString(&u.PhoneNumber, in.PhoneNumber)
+	setString(&u.Country, in.Country)
+	setString(&u.CompanyName, in.CompanyName)
+	u.UpdatedAt = s.now()
+	return http.StatusOK, routefusion.UpdatedUserDetails{UserDetails: *u}
+}
+
+func (s *Server) listUsers(r *request) (int, interface{}) {
+	out := []routefusion.AllUserDetails{}
+	for _, u := range s.users[1:] {
+		out = append(out, *u)
+	}
+	start, end := paginate(r.query, len(out))
+	return http.StatusOK, out[start:end]
+}
+
+func (s *Server) getSubUser(r *request) (int, interface{}) {
+	u, _, err := s.user(r, 0)
+	if err != nil {
+		return err.response()
+	}
+	return http.StatusOK, u
+}
+
+func setString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// beneficiary is a stored beneficiary and the user it belongs to.
+type beneficiary struct {
+	owner string
+	routefusion.Beneficiary
+}
+
+// VerifyBeneficiary marks a beneficiary as verified, as the Routefusion
+// compliance team does, and notifies the webhooks.
+func (s *Server) VerifyBeneficiary(uuid string) bool {
+	s.mu.Lock()
+	b := s.findBeneficiary(func(b *beneficiary) bool { return b.UUID == uuid })
+	if b != nil {
+		s.setBeneficiaryStatus(b, "verified")
+		b.Verified = true
+		s.queueEvent(webhook.EventBeneficiaryVerified, map[string]interface{}{
+			"beneficiary_uuid": b.UUID,
+			"user_uuid":        b.owner,
+			"verified":         true,
+			"status":           b.Status,
+			"updated_at":       b.UpdatedAt,
+		})
+	}
+	events := s.takeEvents()
+	s.mu.Unlock()
+
+	s.deliver(events)
+	return b != nil
+}
+
+func (s *Server) findBeneficiary(match func(b *beneficiary) bool) *beneficiary {
+	for _, b := range s.beneficiaries {
+		if match(b) {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *Server) setBeneficiaryStatus(b *beneficiary, status string) {
+	b.Status = status
+	b.UpdatedAt = s.now()
+	b.StatusHistory = append(b.StatusHistory, struct {
+		Status    string    `json:"status"`
+		CreatedAt time.Time `json:"created_at"`
+	}{Status: status, CreatedAt: b.UpdatedAt})
+}
+
+func (s *Server) listBeneficiaries(r *request) (int, interface{}) {
+	u, _, err := s.user(r, 0)
+	if err != nil {
+		return err.response()
+	}
+
+	out := []routefusion.Beneficiary{}
+	for _, b := range s.beneficiaries {
+		if b.owner == u.UUID {
+			out = append(out, b.Beneficiary)
+		}
+	}
+	start, end := paginate(r.query, len(out))
+	return http.StatusOK, out[start:end]
+}
+
+func (s *Server) getBeneficiary(r *request) (int, interface{}) {
+	u, params, err := s.user(r, 1)
+	if err != nil {
+		return err.response()
+	}
+
+	b := s.findBeneficiary(func(b *beneficiary) bool {
+		return b.owner == u.UUID && b.UUID == params[0]
+	})
+	if b == nil {
+		return newAPIError(http.StatusNotFound, "not_found",
+			"beneficiary not found").response()
+	}
+	return http.StatusOK, b.BeneficiaryBase
+}
+
+func (s *Server) createBeneficiary(r *request) (int, interface{}) {
+	u, _, err := s.user(r, 0)
+	if err != nil {
+		return err.response()
+	}
+	in := routefusion.BeneficiaryInput{}
+	if err := r.decode(&in); err != nil {
+		return err.response()
+	}
+
+	switch {
+	case in.Type == "":
+		return validationError("type", "is required").response()
+	case in.Currency == "":
+		return validationError("currency", "is required").response()
+	case in.BankCountry == "":
+		return validationError("bank_country", "is required").response()
+	}
+
+	b := &beneficiary{owner: u.UUID}
+	b.ID = len(s.beneficiaries) + 1
+	b.UUID = s.newID("beneficiary")
+	b.UserID = s.userID(u)
+	b.CreatedAt = s.now()
+	applyBeneficiaryInput(&b.BeneficiaryBase, &in)
+	s.setBeneficiaryStatus(b, "pending")
+	s.beneficiaries = append(s.beneficiaries, b)
+	return http.StatusCreated, b.BeneficiaryBase
+}
+
+func (s *Server) updateBeneficiary(r *request) (int, interface{}) {
+	u, params, err := s.user(r, 1)
+	if err != nil {
+		return err.response()
+	}
+	in := routefusion.UpdateBeneficiaryInput{}
+	if err := r.decode(&in); err != nil {
+		return err.response()
+	}
+
+	b := s.findBeneficiary(func(b *beneficiary) bool {
+		return b.owner == u.UUID && b.UUID == params[0]
+	})
+	if b == nil {
+		return newAPIError(http.StatusNotFound, "not_found",
+			"beneficiary not found").response()
+	}
+
+	applyBeneficiaryInput(&b.BeneficiaryBase, &routefusion.BeneficiaryInput{
+		Type:               in.Type,
+		FirstNameOnAccount: in.FirstNameOnAccount,
+		LastNameOnAccount:  in.LastNameOnAccount,
+		CompanyName:        in.CompanyName,
+		BankCountry:        in.BankCountry,
+		BankName:           in.BankName,
+		AccountNumber:      in.AccountNumber,
+		Currency:           in.Currency,
+		Address1:           in.Address1,
+		Country:            in.Country,
+		City:               in.City,
+		PostalCode:         in.PostalCode,
+		RoutingNumber:      in.RoutingNumber,
+		SwiftBic:           in.SwiftBic,
+		BsbNumber:          in.BsbNumber,
+		Cpfcnpj:            in.Cpfcnpj,
+		StateProvince:      in.StateProvince,
+		PhoneNumber:        in.PhoneNumber,
+		BranchName:         in.BranchName,
+		BankCity:           in.BankCity,
+		BankStateProvince:  in.BankStateProvince,
+		Clabe:              in.Clabe,
+		BankCode:           in.BankCode,
+		TaxNumber:          in.TaxNumber,
+		BranchCode:         in.BranchCode,
+	})
+	setString(&b.Email, in.Email)
+	setString(&b.AccountType, in.AccountType)
+	setString(&b.BankAddress1, in.BankAddress1)
+	setString(&b.BankPostalCode, in.BankPostalCode)
+	setInterface(&b.Address2, in.Address2)
+	setInterface(&b.BankAddress2, in.BankAddress2)
+	b.UpdatedAt = s.now()
+	return http.StatusOK, b.BeneficiaryBase
+}
+
+// userID returns the numeric id of a user, its position in the account.
+func (s *Server) userID(u *routefusion.AllUserDetails) int {
+	for i, other := range s.users {
+		if other == u {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func applyBeneficiaryInput(b *routefusion.BeneficiaryBase, in *routefusion.BeneficiaryInput) {
+	setString(&b.Type, in.Type)
+	setString(&b.FirstNameOnAccount, in.FirstNameOnAccount)
+	setString(&b.LastNameOnAccount, in.LastNameOnAccount)
+	setString(&b.CompanyName, in.CompanyName)
+	setString(&b.BankCountry, in.BankCountry)
+	setString(&b.BankName, in.BankName)
+	setString(&b.AccountNumber, in.AccountNumber)
+	setString(&b.Currency, in.Currency)
+	setString(&b.Address1, in.Address1)
+	setString(&b.Country, in.Country)
+	setString(&b.City, in.City)
+	setString(&b.PostalCode, in.PostalCode)
+	setString(&b.RoutingNumber, in.RoutingNumber)
+	setString(&b.SwiftBic, in.SwiftBic)
+	setString(&b.StateProvince, in.StateProvince)
+	setString(&b.BankCity, in.BankCity)
+	setString(&b.BankStateProvince, in.BankStateProvince)
+	setInterface(&b.BsbNumber, in.BsbNumber)
+	setInterface(&b.Cpfcnpj, in.Cpfcnpj)
+	setInterface(&b.PhoneNumber, in.PhoneNumber)
+	setInterface(&b.BranchName, in.BranchName)
+	setInterface(&b.Clabe, in.Clabe)
+	setInterface(&b.BankCode, in.BankCode)
+	setInterface(&b.TaxNumber, in.TaxNumber)
+	setInterface(&b.BranchCode, in.BranchCode)
+}
+
+func setInterface(dst *interface{}, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+func (s *Server) createQuote(r *request) (int, interface{}) {
+	in := routefusion.QuoteInput{}
+	if err := r.decode(&in); err != nil {
+		return err.response()
+	}
+
+	switch {
+	case in.SourceCurrency == "":
+		return validationError("source_currency", "is required").response()
+	case in.DestinationCurrency == "":
+		return validationError("destination_currency", "is required").response()
+	case in.SourceAmount.Sign() <= 0:
+		return validationError("source_amount", "must be positive").response()
+	}
+
+	now := s.now()
+	rate := s.rate(in.SourceCurrency, in.DestinationCurrency)
+	q := &routefusion.QuoteResponse{
+		UUID:                s.newID("quote"),
+		SourceCurrency:      strings.ToUpper(in.SourceCurrency),
+		DestinationCurrency: strings.ToUpper(in.DestinationCurrency),
+		Rate:                rate,
+		InvertedRate:        routefusion.NewDecimalFromInt(1).Div(rate, 8),
+		DateOfPayment:       now,
+		ExpiresAt:           now.Add(s.config.QuoteTTL),
+		CreatedAt:           now,
+	}
+	s.quotes[q.UUID] = q
+	return http.StatusCreated, q
+}
+
+func (s *Server) getBalance(r *request) (int, interface{}) {
+	return http.StatusOK, map[string]interface{}{
+		"currency": s.config.Currency,
+		"balance":  s.balance,
+	}
+}
+
+func (s *Server) findWebhook(id string) *routefusion.WebhookResponse {
+	for _, w := range s.webhooks {
+		if w.UUID == id {
+			return w
+		}
+	}
+	return nil
+}
+
+func (s *Server) listWebhooks(r *request) (int, interface{}) {
+	out := []routefusion.WebhookResponse{}
+	for _, w := range s.webhooks {
+		out = append(out, *w)
+	}
+	start, end := paginate(r.query, len(out))
+	return http.StatusOK, out[start:end]
+}
+
+func (s *Server) getWebhook(r *request) (int, interface{}) {
+	w := s.findWebhook(r.params[0])
+	if w == nil {
+		return newAPIError(http.StatusNotFound, "not_found", "webhook not found").response()
+	}
+	return http.StatusOK, w
+}
+
+func (s *Server) createWebhook(r *request) (int, interface{}) {
+	in := routefusion.WebhookUpdateInput{}
+	if err := r.decode(&in); err != nil {
+		return err.response()
+	}
+	if in.URL == "" {
+		return validationError("url", "is required").response()
+	}
+
+	w := &routefusion.WebhookResponse{
+		UUID:      s.newID("webhook"),
+		URL:       in.URL,
+		Type:      in.Type,
+		Rfuuid:    in.RFUUID,
+		CreatedAt: s.now(),
+		UpdatedAt: s.now(),
+	}
+	s.webhooks = append(s.webhooks, w)
+	return http.StatusCreated, w
+}
+
+func (s *Server) updateWebhook(r *request) (int, interface{}) {
+	in := routefusion.WebhookUpdateInput{}
+	if err := r.decode(&in); err != nil {
+		return err.response()
+	}
+
+	w := s.findWebhook(r.params[0])
+	if w == nil {
+		return newAPIError(http.StatusNotFound, "not_found", "webhook not found").response()
+	}
+	setString(&w.URL, in.URL)
+	setString(&w.Type, in.Type)
+	setString(&w.Rfuuid, in.RFUUID)
+	w.UpdatedAt = s.now()
+	return http.StatusOK, w
+}
+
+func (s *Server) deleteWebhook(r *request) (int, interface{}) {
+	for i, w := range s.webhooks {
+		if w.UUID == r.params[0] {
+			s.webhooks = append(s.webhooks[:i:i], s.webhooks[i+1:]...)
+			return http.StatusNoContent, nil
+		}
+	}
+	return newAPIError(http.StatusNotFound, "not_found", "webhook not found").response()
+}
@@ -0,0 +1,414 @@
+// Package routefusiontest provides an in-process fake of the Routefusion API
+// for integration tests.
+//
+// The fake keeps users, beneficiaries, quotes, transfers, the account balance
+// and webhooks in memory and serves them from an httptest.Server:
+//
+//	s := routefusiontest.NewServer(routefusiontest.Config{})
+//	defer s.Close()
+//	api := routefusion.New(s.ClientConfig())
+//
+// Tests drive what the real API does asynchronously, such as transfers
+// progressing or beneficiaries being verified, through methods of Server, and
+// inject failures, latency and rate limiting with Inject.
+package routefusiontest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/routefusion/routefusion-golang"
+	"github.com/routefusion/routefusion-golang/client"
+)
+
+const (
+	defaultCurrency = "USD"
+	defaultQuoteTTL = time.Minute
+
+	// defaultWebhookSecret signs events when Config.WebhookSecret is empty,
+	// as webhook.NewHandler refuses empty secrets.
+	defaultWebhookSecret = "routefusiontest-webhook-secret"
+
+	headerIdempotencyKey      = "Idempotency-Key"
+	headerIdempotentReplayed  = "Idempotent-Replayed"
+	headerAuthorization       = "Authorization"
+	bearerAuthorizationPrefix = "Bearer "
+)
+
+// Config configures a Server. The zero value is ready to use.
+type Config struct {
+	// Token is the bearer token clients must authenticate with. Any token is
+	// accepted if empty.
+	Token string
+
+	// Currency is the currency of the account balance, USD if empty.
+	Currency string
+
+	// WebhookSecret signs the events delivered to registered webhooks. A
+	// fixed secret, returned by Server.WebhookSecret, is used if empty.
+	WebhookSecret string
+
+	// QuoteTTL is the validity of quotes, one minute if zero.
+	QuoteTTL time.Duration
+
+	// TransferFee is charged in the source currency on every transfer.
+	TransferFee routefusion.Decimal
+
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// RecordedRequest is a request received by the Server.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake Routefusion API. All its methods are safe for concurrent
+// use.
+type Server struct {
+	// URL is the base URL of the server, to be used as client.Config.BaseURL.
+	URL string
+
+	config Config
+	server *httptest.Server
+
+	mu            sync.Mutex
+	nextID        int
+	master        *routefusion.AllUserDetails
+	users         []*routefusion.AllUserDetails
+	beneficiaries []*beneficiary
+	quotes        map[string]*routefusion.QuoteResponse
+	transfers     []*transfer
+	webhooks      []*routefusion.WebhookResponse
+	rates         map[string]routefusion.Decimal
+	balance       routefusion.Decimal
+	idempotent    map[string]recordedResponse
+	requests      []RecordedRequest
+	faults        []*Fault
+	latency       time.Duration
+	events        []event
+}
+
+// recordedResponse is the response replayed for a reused idempotency key.
+type recordedResponse struct {
+	status int
+	body   []byte
+}
+
+// NewServer starts and returns a new Server. It should be closed with Close.
+func NewServer(config Config) *Server {
+	if config.Currency == "" {
+		config.Currency = defaultCurrency
+	}
+	if config.QuoteTTL <= 0 {
+		config.QuoteTTL = defaultQuoteTTL
+	}
+	if config.WebhookSecret == "" {
+		config.WebhookSecret = defaultWebhookSecret
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	s := &Server{
+		config:     config,
+		quotes:     map[string]*routefusion.QuoteResponse{},
+		rates:      map[string]routefusion.Decimal{},
+		idempotent: map[string]recordedResponse{},
+	}
+	s.master = &routefusion.AllUserDetails{
+		UserDetails: routefusion.UserDetails{
+			UUID:       s.newID("user"),
+			Country:    "US",
+			Verified:   true,
+			MasterUser: true,
+			CreatedAt:  s.now(),
+			UpdatedAt:  s.now(),
+		},
+		Admin: true,
+	}
+	s.users = append(s.users, s.master)
+
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// WebhookSecret returns the secret the events delivered to webhooks are
+// signed with, to configure a webhook.Handler receiving them.
+func (s *Server) WebhookSecret() string {
+	return s.config.WebhookSecret
+}
+
+// ClientConfig returns a client configuration pointing at the server and
+// authenticated with its token.
+func (s *Server) ClientConfig() client.Config {
+	token := s.config.Token
+	if token == "" {
+		token = "routefusiontest"
+	}
+	return client.Config{
+		BaseURL:    s.URL,
+		Authorizer: &client.BearerTokenAuthorizer{Token: token},
+	}
+}
+
+// Requests returns the requests received so far, including the ones a fault
+// was injected into.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+func (s *Server) now() time.Time {
+	return s.config.Now().UTC()
+}
+
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return prefix + "-" + strconv.Itoa(s.nextID)
+}
+
+// request is a received request, as seen by the route handlers.
+type request struct {
+	params []string
+	query  url.Values
+	body   []byte
+}
+
+func (r *request) decode(v interface{}) *apiError {
+	if err := json.Unmarshal(r.body, v); err != nil {
+		return newAPIError(http.StatusBadRequest, "invalid_body", err.Error())
+	}
+	return nil
+}
+
+// route maps a method and a path pattern, whose "*" segments match any
+// value, to its handler. Handlers run with the server lock held and return
+// the status code and the value to encode as the response body.
+type route struct {
+	method  string
+	pattern []string
+	handle  func(s *Server, r *request) (int, interface{})
+}
+
+func newRoute(method, pattern string,
+	handle func(s *Server, r *request) (int, interface{})) route {
+	return route{method: method, pattern: strings.Split(pattern, "/"), handle: handle}
+}
+
+// match reports whether the route serves method and path and returns the
+// values of the path wildcards.
+func (rt route) match(method string, path []string) ([]string, bool) {
+	if rt.method != method || len(rt.pattern) != len(path) {
+		return nil, false
+	}
+
+	var params []string
+	for i, segment := range rt.pattern {
+		switch segment {
+		case "*":
+			params = append(params, path[i])
+		case path[i]:
+		default:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+var routes = []route{
+	newRoute(http.MethodGet, "/v1/users/me", (*Server).getUser),
+	newRoute(http.MethodPut, "/v1/users/me", (*Server).updateUser),
+	newRoute(http.MethodGet, "/v1/users", (*Server).listUsers),
+	newRoute(http.MethodGet, "/v1/users/*", (*Server).getSubUser),
+
+	newRoute(http.MethodGet, "/v1/beneficiaries", (*Server).listBeneficiaries),
+	newRoute(http.MethodPost, "/v1/beneficiaries", (*Server).createBeneficiary),
+	newRoute(http.MethodGet, "/v1/beneficiaries/*", (*Server).getBeneficiary),
+	newRoute(http.MethodPut, "/v1/beneficiaries/*", (*Server).updateBeneficiary),
+	newRoute(http.MethodGet, "/v1/users/*/beneficiaries", (*Server).listBeneficiaries),
+	newRoute(http.MethodPost, "/v1/users/*/beneficiaries", (*Server).createBeneficiary),
+	newRoute(http.MethodGet, "/v1/users/*/beneficiaries/*", (*Server).getBeneficiary),
+	newRoute(http.MethodPut, "/v1/users/*/beneficiaries/*", (*Server).updateBeneficiary),
+
+	newRoute(http.MethodPost, "/v1/quotes", (*Server).createQuote),
+
+	newRoute(http.MethodPost, "/v1/transfers", (*Server).createTransfer),
+	newRoute(http.MethodGet, "/v1/transfers/*", (*Server).getTransfer),
+	newRoute(http.MethodPost, "/v1/transfers/*/cancel", (*Server).cancelTransfer),
+	newRoute(http.MethodPost, "/v1/users/*/transfers", (*Server).createTransfer),
+	newRoute(http.MethodGet, "/v1/users/*/transfers/*", (*Server).getTransfer),
+	newRoute(http.MethodGet, "/v1/users/*/transfers/*/status", (*Server).getTransferStatus),
+	newRoute(http.MethodPost, "/v1/users/*/transfers/*/cancel", (*Server).cancelTransfer),
+
+	newRoute(http.MethodGet, "/v1/transactions", (*Server).listTransactions),
+	newRoute(http.MethodGet, "/v1/balance", (*Server).getBalance),
+
+	newRoute(http.MethodGet, "/v1/webhooks", (*Server).listWebhooks),
+	newRoute(http.MethodPost, "/v1/webhooks", (*Server).createWebhook),
+	newRoute(http.MethodGet, "/v1/webhooks/*", (*Server).getWebhook),
+	newRoute(http.MethodPut, "/v1/webhooks/*", (*Server).updateWebhook),
+	newRoute(http.MethodDelete, "/v1/webhooks/*", (*Server).deleteWebhook),
+}
+
+// ServeHTTP records the request, applies the matching fault, if any, and
+// routes the request to its handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	fault := s.takeFault(r.Method, r.URL.Path)
+	latency := s.latency
+	s.mu.Unlock()
+
+	if !delay(r, latency) {
+		return
+	}
+	if fault != nil && fault.apply(w, r) {
+		return
+	}
+
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, newAPIError(http.StatusUnauthorized,
+			"unauthorized", "missing or invalid bearer token"))
+		return
+	}
+
+	s.mu.Lock()
+	status, payload, replayed := s.serve(r, body)
+	events := s.takeEvents()
+	s.mu.Unlock()
+
+	if replayed {
+		w.Header().Set(headerIdempotentReplayed, "true")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(payload)
+
+	s.deliver(events)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	auth := r.Header.Get(headerAuthorization)
+	if !strings.HasPrefix(auth, bearerAuthorizationPrefix) {
+		return false
+	}
+	return s.config.Token == "" ||
+		strings.TrimPrefix(auth, bearerAuthorizationPrefix) == s.config.Token
+}
+
+// serve runs the handler of the request, or replays the response of an
+// earlier request with the same idempotency key, and returns the encoded
+// response.
+func (s *Server) serve(r *http.Request, body []byte) (int, []byte, bool) {
+	key := r.Header.Get(headerIdempotencyKey)
+	if key != "" {
+		key = r.Method + " " + r.URL.Path + " " + key
+		if recorded, ok := s.idempotent[key]; ok {
+			return recorded.status, recorded.body, true
+		}
+	}
+
+	status, v := http.StatusNotFound, interface{}(newAPIError(http.StatusNotFound,
+		"not_found", "no route for "+r.Method+" "+r.URL.Path))
+	path := strings.Split(r.URL.Path, "/")
+	for _, rt := range routes {
+		if params, ok := rt.match(r.Method, path); ok {
+			status, v = rt.handle(s, &request{params: params, query: r.URL.Query(),
+				body: body})
+			break
+		}
+	}
+
+	payload := []byte{}
+	if v != nil {
+		payload, _ = json.Marshal(v)
+	}
+	if key != "" && status < http.StatusInternalServerError {
+		s.idempotent[key] = recordedResponse{status: status, body: payload}
+	}
+	return status, payload, false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// apiError is an error response in the format of the Routefusion API.
+type apiError struct {
+	status  int
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Errors  []fieldError `json:"errors,omitempty"`
+}
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func newAPIError(status int, code, message string) *apiError {
+	return &apiError{status: status, Code: code, Message: message}
+}
+
+func validationError(field, message string) *apiError {
+	err := newAPIError(http.StatusUnprocessableEntity, "validation_failed",
+		"the request is invalid")
+	err.Errors = []fieldError{{Field: field, Message: message}}
+	return err
+}
+
+// response returns the status code and the body of the error response.
+func (e *apiError) response() (int, interface{}) {
+	return e.status, e
+}
+
+// paginate returns the page of a list of n items selected by the page and
+// limit query parameters, as the bounds of the page.
+func paginate(query url.Values, n int) (int, int) {
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 {
+		return 0, n
+	}
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+
+	start, end := (page-1)*limit, page*limit
+	if start > n {
+		start = n
+	}
+	if end > n {
+		end = n
+	}
+	return start, end
+}
@@ -0,0 +1,426 @@
+package routefusiontest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/routefusion/routefusion-golang"
+	"github.com/routefusion/routefusion-golang/client"
+	"github.com/routefusion/routefusion-golang/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAPI(config Config) (*routefusion.API, *Server) {
+	s := NewServer(config)
+	c := s.ClientConfig()
+	c.Retryer = client.DefaultRetryer{NumMaxRetries: 2, MaxRetryAfter: time.Millisecond}
+	return routefusion.New(c), s
+}
+
+// createBeneficiary creates an MXN beneficiary and returns its numeric id.
+func createBeneficiary(t *testing.T, api *routefusion.API) int {
+	b, err := api.CreateBeneficiary(&routefusion.BeneficiaryInput{
+		Type:        "personal",
+		BankCountry: "MX",
+		BankName:    "Banorte",
+		Currency:    "MXN",
+		Clabe:       "072580010055555551",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.ID
+}
+
+func decimalPtr(s string) *routefusion.Decimal {
+	d := routefusion.MustParseDecimal(s)
+	return &d
+}
+
+func assertDecimal(t *testing.T, expected string, actual routefusion.Decimal) {
+	t.Helper()
+	assert.True(t, routefusion.MustParseDecimal(expected).Equal(actual),
+		"expected %s, got %s", expected, actual)
+}
+
+func assertAPIError(t *testing.T, err error, status int, code string) {
+	t.Helper()
+	serviceErr, ok := err.(client.ServiceError)
+	if !assert.True(t, ok, "expected a service error, got %v", err) {
+		return
+	}
+	assert.Equal(t, status, serviceErr.StatusCode())
+	assert.Equal(t, code, serviceErr.APICode())
+}
+
+func Test_ServerUsersAndBeneficiaries(t *testing.T) {
+	api, s := newTestAPI(Config{})
+	defer s.Close()
+
+	me, err := api.UpdateUser(&routefusion.User{UserData: routefusion.UserData{
+		FirstName: "Ada"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Ada", me.FirstName)
+	user, _ := api.GetUser()
+	assert.Equal(t, "Ada", user.FirstName)
+	assert.True(t, user.MasterUser)
+
+	id := createBeneficiary(t, api)
+	all, err := api.ListBeneficiaries(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, all, 1)
+	assert.Equal(t, id, all[0].ID)
+	assert.Equal(t, "pending", all[0].Status)
+	assert.Equal(t, "072580010055555551", all[0].Clabe)
+
+	sub := s.AddSubUser(routefusion.AllUserDetails{City: "Austin"})
+	users, _ := api.ListUsersMaster(nil)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "Austin", users[0].City)
+
+	created, err := api.CreateSubUserBeneficiaryMaster(sub.UUID, &routefusion.BeneficiaryInput{
+		Type: "business", BankCountry: "US", Currency: "USD", CompanyName: "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := api.GetSubUserBeneficiaryMaster(sub.UUID, created.UUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Acme", got.CompanyName)
+
+	// Beneficiaries are scoped to their user.
+	_, err = api.GetBeneficiary(created.UUID)
+	assertAPIError(t, err, http.StatusNotFound, "not_found")
+
+	_, err = api.CreateBeneficiary(&routefusion.BeneficiaryInput{Type: "personal"})
+	assertAPIError(t, err, http.StatusUnprocessableEntity, "validation_failed")
+	assert.Equal(t, "currency", err.(client.ServiceError).FieldErrors()[0].Field)
+}
+
+func Test_ServerTransferLifecycle(t *testing.T) {
+	api, s := newTestAPI(Config{TransferFee: routefusion.MustParseDecimal("2")})
+	defer s.Close()
+
+	s.SetBalance(routefusion.MustParseDecimal("1000"))
+	s.SetRate("USD", "MXN", routefusion.MustParseDecimal("17.25"))
+	id := createBeneficiary(t, api)
+
+	q, err := api.CreateQuote(&routefusion.QuoteInput{SourceAmount: routefusion.MustParseDecimal("100"),
+		SourceCurrency: "USD", DestinationCurrency: "MXN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "17.25", q.Rate.String())
+	assert.Equal(t, "0.05797101", q.InvertedRate.String())
+
+	tr, err := api.CreateTransfer(&routefusion.TransferInput{BeneficiaryID: id,
+		SourceAmount: decimalPtr("100"), QuoteUUID: q.UUID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, routefusion.TransferCreated, tr.State)
+	assert.Equal(t, "1725.00", tr.DestinationAmount.String())
+	assertDecimal(t, "898", s.Balance())
+
+	assert.NoError(t, s.AdvanceTransfer(tr.UUID, routefusion.TransferProcessing))
+	assert.Error(t, s.AdvanceTransfer(tr.UUID, routefusion.TransferCreated))
+	assert.NoError(t, s.AdvanceTransfer(tr.UUID, routefusion.TransferCompleted))
+
+	got, err := api.GetTransfer(tr.UUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, routefusion.TransferCompleted, got.State)
+	assert.Len(t, got.TransferStates, 3)
+
+	_, err = api.CancelTransfer(tr.UUID)
+	assertAPIError(t, err, http.StatusConflict, "invalid_state")
+
+	transactions, err := api.GetTransactions(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, transactions, 1)
+	assert.Equal(t, "17.25", transactions[0].ExchangeRate.String())
+
+	balance, err := api.GetBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "USD", balance.Currency)
+	assertDecimal(t, "898", balance.Balance)
+}
+
+func Test_ServerTransferFailures(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	api, s := newTestAPI(Config{Now: func() time.Time { return now }})
+	defer s.Close()
+
+	s.SetBalance(routefusion.MustParseDecimal("50"))
+	id := createBeneficiary(t, api)
+
+	_, err := api.CreateTransfer(&routefusion.TransferInput{BeneficiaryID: id,
+		SourceAmount: decimalPtr("60")})
+	assertAPIError(t, err, http.StatusUnprocessableEntity, "insufficient_funds")
+
+	_, err = api.CreateTransfer(&routefusion.TransferInput{BeneficiaryID: id})
+	assertAPIError(t, err, http.StatusUnprocessableEntity, "validation_failed")
+
+	q, _ := api.CreateQuote(&routefusion.QuoteInput{SourceAmount: routefusion.MustParseDecimal("10"),
+		SourceCurrency: "USD", DestinationCurrency: "MXN"})
+	now = now.Add(2 * time.Minute)
+	_, err = api.CreateTransfer(&routefusion.TransferInput{BeneficiaryID: id,
+		SourceAmount: decimalPtr("10"), QuoteUUID: q.UUID})
+	assertAPIError(t, err, http.StatusUnprocessableEntity, "quote_expired")
+
+	tr, err := api.CreateTransfer(&routefusion.TransferInput{BeneficiaryID: id,
+		DestinationAmount: decimalPtr("20")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDecimal(t, "30", s.Balance())
+
+	uuid, err := api.CancelTransfer(tr.UUID)
+	assert.NoError(t, err)
+	assert.Equal(t, tr.UUID, uuid)
+	assertDecimal(t, "50.00", s.Balance())
+}
+
+func Test_ServerIdempotency(t *testing.T) {
+	api, s := newTestAPI(Config{})
+	defer s.Close()
+
+	s.SetBalance(routefusion.MustParseDecimal("100"))
+	id := createBeneficiary(t, api)
+
+	ctx := client.WithIdempotencyKey(context.Background(), "key-1")
+	in := &routefusion.TransferInput{BeneficiaryID: id, SourceAmount: decimalPtr("10")}
+	first, err := api.CreateTransferWithContext(ctx, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := api.CreateTransferWithContext(ctx, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, first.UUID, second.UUID)
+	assertDecimal(t, "90", s.Balance())
+}
+
+func Test_ServerWebhooks(t *testing.T) {
+	h, err := webhook.NewHandler(webhook.Config{Secret: "whsec"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var states []string
+	var verified []string
+	h.OnTransferStateChanged(func(ctx context.Context, e *webhook.Event,
+		data *webhook.TransferStateChanged) error {
+		states = append(states, data.PreviousState+">"+data.State)
+		return nil
+	})
+	h.OnBeneficiaryVerified(func(ctx context.Context, e *webhook.Event,
+		data *webhook.BeneficiaryVerified) error {
+		verified = append(verified, data.BeneficiaryUUID)
+		return nil
+	})
+	receiver := httptest.NewServer(h)
+	defer receiver.Close()
+
+	api, s := newTestAPI(Config{WebhookSecret: "whsec"})
+	defer s.Close()
+
+	transfers, err := api.CreateWebhook(routefusion.WebhookUpdateInput{URL: receiver.URL,
+		Type: string(webhook.EventTransferStateChanged)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	beneficiaries, err := api.CreateWebhook(routefusion.WebhookUpdateInput{
+		URL: receiver.URL, Type: string(webhook.EventBeneficiaryVerified)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.SetBalance(routefusion.MustParseDecimal("100"))
+	id := createBeneficiary(t, api)
+	tr, err := api.CreateTransfer(&routefusion.TransferInput{BeneficiaryID: id,
+		SourceAmount: decimalPtr("10"), AutoComplete: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, routefusion.TransferCompleted, tr.State)
+	assert.Equal(t, []string{">created", "created>processing", "processing>completed"},
+		states)
+
+	all, _ := api.ListBeneficiaries(nil)
+	assert.True(t, s.VerifyBeneficiary(all[0].UUID))
+	assert.False(t, s.VerifyBeneficiary("beneficiary-0"))
+	assert.Equal(t, []string{all[0].UUID}, verified)
+
+	assert.NoError(t, api.DeleteWebhook(beneficiaries.UUID))
+	_, err = api.UpdateWebhook(transfers.UUID, routefusion.WebhookUpdateInput{Type: ""})
+	assert.NoError(t, err)
+	hooks, _ := api.IndexWebhooks(nil)
+	assert.Len(t, hooks, 1)
+	assert.Equal(t, 0, hooks[0].FailedCount)
+
+	// Deliveries signed with another secret are refused by the receiver and
+	// counted as failed.
+	otherAPI, other := newTestAPI(Config{WebhookSecret: "other"})
+	defer other.Close()
+	hook, _ := otherAPI.CreateWebhook(routefusion.WebhookUpdateInput{URL: receiver.URL})
+	createBeneficiary(t, otherAPI)
+	got, _ := otherAPI.GetWebhook(hook.UUID)
+	assert.Equal(t, 0, got.FailedCount)
+
+	other.SetBalance(routefusion.MustParseDecimal("100"))
+	_, err = otherAPI.CreateTransfer(&routefusion.TransferInput{BeneficiaryID: 1,
+		SourceAmount: decimalPtr("10")})
+	assert.NoError(t, err)
+	got, _ = otherAPI.GetWebhook(hook.UUID)
+	assert.Equal(t, 1, got.FailedCount)
+	assert.Len(t, states, 3)
+}
+
+func Test_ServerWebhooksDefaultSecret(t *testing.T) {
+	api, s := newTestAPI(Config{})
+	defer s.Close()
+
+	h, err := webhook.NewHandler(webhook.Config{Secret: s.WebhookSecret()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var verified []string
+	h.OnBeneficiaryVerified(func(ctx context.Context, e *webhook.Event,
+		data *webhook.BeneficiaryVerified) error {
+		verified = append(verified, data.BeneficiaryUUID)
+		return nil
+	})
+	receiver := httptest.NewServer(h)
+	defer receiver.Close()
+
+	hook, err := api.CreateWebhook(routefusion.WebhookUpdateInput{URL: receiver.URL,
+		Type: string(webhook.EventBeneficiaryVerified)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createBeneficiary(t, api)
+	all, _ := api.ListBeneficiaries(nil)
+	assert.True(t, s.VerifyBeneficiary(all[0].UUID))
+	assert.Equal(t, []string{all[0].UUID}, verified)
+
+	got, _ := api.GetWebhook(hook.UUID)
+	assert.Equal(t, 0, got.FailedCount)
+}
+
+func Test_ServerFaults(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		fault            func(s *Server)
+		ctxTimeout       time.Duration
+		expectedRequests int
+		expectedCode     string
+	}{
+		{
+			desc:             "rate limited requests are retried",
+			fault:            func(s *Server) { s.RateLimitNext(2, time.Second) },
+			expectedRequests: 3,
+		},
+		{
+			desc:             "server errors are retried",
+			fault:            func(s *Server) { s.FailNext(1, http.StatusInternalServerError) },
+			expectedRequests: 2,
+		},
+		{
+			desc: "dropped connections are retried",
+			fault: func(s *Server) {
+				s.Inject(Fault{Path: "/v1/balance", Times: 1, Disconnect: true})
+			},
+			expectedRequests: 2,
+		},
+		{
+			desc:             "retries give up",
+			fault:            func(s *Server) { s.FailNext(5, http.StatusServiceUnavailable) },
+			expectedRequests: 3,
+			expectedCode:     client.ErrCodeUndefined,
+		},
+		{
+			desc:             "latency",
+			fault:            func(s *Server) { s.SetLatency(time.Second) },
+			ctxTimeout:       20 * time.Millisecond,
+			expectedRequests: 1,
+			expectedCode:     client.ErrCodeRequestCanceled,
+		},
+		{
+			desc: "latency does not hide other faults",
+			fault: func(s *Server) {
+				s.SetLatency(time.Millisecond)
+				s.FailNext(1, http.StatusBadRequest)
+			},
+			expectedRequests: 1,
+			expectedCode:     client.ErrCodeUndefined,
+		},
+		{
+			desc: "latency is removed",
+			fault: func(s *Server) {
+				s.SetLatency(time.Second)
+				s.SetLatency(0)
+			},
+			ctxTimeout:       500 * time.Millisecond,
+			expectedRequests: 1,
+		},
+		{
+			desc: "faults restricted to other paths do not apply",
+			fault: func(s *Server) {
+				s.Inject(Fault{Method: http.MethodPost, Path: "/v1/*",
+					Status: http.StatusBadGateway})
+			},
+			expectedRequests: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			api, s := newTestAPI(Config{})
+			defer s.Close()
+			testCase.fault(s)
+
+			ctx := context.Background()
+			if testCase.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, testCase.ctxTimeout)
+				defer cancel()
+			}
+
+			_, err := api.GetBalanceWithContext(ctx)
+			if testCase.expectedCode == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Equal(t, testCase.expectedCode, err.(client.RFError).Code())
+			}
+			assert.Len(t, s.Requests(), testCase.expectedRequests)
+		})
+	}
+}
+
+func Test_ServerAuthorization(t *testing.T) {
+	api, s := newTestAPI(Config{Token: "secret"})
+	defer s.Close()
+
+	_, err := api.GetUser()
+	assert.NoError(t, err)
+
+	other := routefusion.New(client.Config{BaseURL: s.URL,
+		Authorizer: &client.BearerTokenAuthorizer{Token: "wrong"}})
+	_, err = other.GetUser()
+	assertAPIError(t, err, http.StatusUnauthorized, "unauthorized")
+}
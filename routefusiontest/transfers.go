@@ -0,0 +1,234 @@
+package routefusiontest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/routefusion/routefusion-golang"
+	"github.com/routefusion/routefusion-golang/webhook"
+)
+
+// transfer is a stored transfer, the user it belongs to and the amount taken
+// from the balance to fund it.
+type transfer struct {
+	owner   string
+	charged routefusion.Decimal
+	routefusion.TransferResponse
+}
+
+// AdvanceTransfer moves a transfer to state, as Routefusion does while
+// processing it, and notifies the webhooks. Failed and cancelled transfers
+// are refunded.
+func (s *Server) AdvanceTransfer(uuid string, state routefusion.TransferStatus) error {
+	s.mu.Lock()
+	var err error
+	if t := s.findTransfer(func(t *transfer) bool { return t.UUID == uuid }); t == nil {
+		err = fmt.Errorf("routefusiontest: transfer %s not found", uuid)
+	} else {
+		err = s.setTransferState(t, state)
+	}
+	events := s.takeEvents()
+	s.mu.Unlock()
+
+	s.deliver(events)
+	return err
+}
+
+// Transfer returns the current version of a transfer.
+func (s *Server) Transfer(uuid string) (routefusion.TransferResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t := s.findTransfer(func(t *transfer) bool { return t.UUID == uuid }); t != nil {
+		return t.TransferResponse, true
+	}
+	return routefusion.TransferResponse{}, false
+}
+
+func (s *Server) findTransfer(match func(t *transfer) bool) *transfer {
+	for _, t := range s.transfers {
+		if match(t) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) setTransferState(t *transfer, state routefusion.TransferStatus) error {
+	if !t.State.CanTransitionTo(state) {
+		return fmt.Errorf("routefusiontest: transfer %s cannot go from %q to %q",
+			t.UUID, t.State, state)
+	}
+
+	previous, now := t.State, s.now()
+	t.State = state
+	t.UpdatedAt = now
+	t.TransferStates = append(t.TransferStates,
+		routefusion.TransferState{State: state, CreatedAt: now})
+
+	if state.IsFailed() || state == routefusion.TransferCancelled {
+		s.balance = s.balance.Add(t.charged)
+	}
+
+	s.queueEvent(webhook.EventTransferStateChanged, map[string]interface{}{
+		"transfer_uuid":  t.UUID,
+		"user_uuid":      t.owner,
+		"state":          state,
+		"previous_state": previous,
+		"updated_at":     now,
+	})
+	return nil
+}
+
+func (s *Server) createTransfer(r *request) (int, interface{}) {
+	u, _, err := s.user(r, 0)
+	if err != nil {
+		return err.response()
+	}
+	in := routefusion.TransferInput{}
+	if err := r.decode(&in); err != nil {
+		return err.response()
+	}
+
+	b := s.findBeneficiary(func(b *beneficiary) bool {
+		return b.owner == u.UUID && b.ID == in.BeneficiaryID
+	})
+	if b == nil {
+		return validationError("beneficiary_id", "not found").response()
+	}
+	if (in.SourceAmount == nil) == (in.DestinationAmount == nil) {
+		return validationError("source_amount",
+			"exactly one of source_amount and destination_amount is required").response()
+	}
+
+	source, destination := s.config.Currency, b.Currency
+	rate := s.rate(source, destination)
+	if in.QuoteUUID != "" {
+		q, ok := s.quotes[in.QuoteUUID]
+		switch {
+		case !ok:
+			return validationError("quote_uuid", "not found").response()
+		case s.now().After(q.ExpiresAt):
+			return newAPIError(http.StatusUnprocessableEntity, "quote_expired",
+				"the quote has expired").response()
+		case q.SourceCurrency != source:
+			return validationError("quote_uuid",
+				"source currency is not the account currency").response()
+		case q.DestinationCurrency != destination:
+			return validationError("quote_uuid",
+				"destination currency is not the beneficiary currency").response()
+		}
+		rate = q.Rate
+	}
+
+	var sourceAmount, destinationAmount routefusion.Decimal
+	if in.SourceAmount != nil {
+		sourceAmount = in.SourceAmount.RoundToCurrency(source)
+		destinationAmount = sourceAmount.Mul(rate).RoundToCurrency(destination)
+	} else {
+		destinationAmount = in.DestinationAmount.RoundToCurrency(destination)
+		sourceAmount = destinationAmount.Div(rate, routefusion.CurrencyMinorUnits(source))
+	}
+	if sourceAmount.Sign() <= 0 || destinationAmount.Sign() <= 0 {
+		return validationError("source_amount", "must be positive").response()
+	}
+
+	charged := sourceAmount.Add(s.config.TransferFee)
+	if s.balance.LessThan(charged) {
+		return newAPIError(http.StatusUnprocessableEntity, "insufficient_funds",
+			"the balance does not cover the transfer").response()
+	}
+	s.balance = s.balance.Sub(charged)
+
+	t := &transfer{owner: u.UUID, charged: charged}
+	t.UUID = s.newID("transfer")
+	t.UserID = s.userID(u)
+	t.BeneficiaryID = b.ID
+	t.SourceAmount = sourceAmount
+	t.SourceCurrency = source
+	t.DestinationAmount = destinationAmount
+	t.DestinationCurrency = destination
+	t.CurrencyPairs = source + destination
+	t.ExchangeRate = rate
+	t.Fee = s.config.TransferFee
+	t.Reference = in.Reference
+	t.AuthorizingIP = "127.0.0.1"
+	t.CreatedAt = s.now()
+	s.setTransferState(t, routefusion.TransferCreated)
+	if in.AutoComplete {
+		s.setTransferState(t, routefusion.TransferProcessing)
+		s.setTransferState(t, routefusion.TransferCompleted)
+	}
+	s.transfers = append(s.transfers, t)
+	return http.StatusCreated, t.TransferResponse
+}
+
+// ownedTransfer returns the transfer selected by the path of r.
+func (s *Server) ownedTransfer(r *request) (*transfer, *apiError) {
+	u, params, err := s.user(r, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	t := s.findTransfer(func(t *transfer) bool {
+		return t.owner == u.UUID && t.UUID == params[0]
+	})
+	if t == nil {
+		return nil, newAPIError(http.StatusNotFound, "not_found", "transfer not found")
+	}
+	return t, nil
+}
+
+func (s *Server) getTransfer(r *request) (int, interface{}) {
+	t, err := s.ownedTransfer(r)
+	if err != nil {
+		return err.response()
+	}
+	return http.StatusOK, t.TransferResponse
+}
+
+func (s *Server) getTransferStatus(r *request) (int, interface{}) {
+	t, err := s.ownedTransfer(r)
+	if err != nil {
+		return err.response()
+	}
+	last := t.TransferStates[len(t.TransferStates)-1]
+	return http.StatusOK, last
+}
+
+func (s *Server) cancelTransfer(r *request) (int, interface{}) {
+	t, err := s.ownedTransfer(r)
+	if err != nil {
+		return err.response()
+	}
+	if err := s.setTransferState(t, routefusion.TransferCancelled); err != nil {
+		return newAPIError(http.StatusConflict, "invalid_state", err.Error()).response()
+	}
+	return http.StatusOK, map[string]string{"uuid": t.UUID}
+}
+
+func (s *Server) listTransactions(r *request) (int, interface{}) {
+	out := []routefusion.TransactionResponse{}
+	for _, t := range s.transfers {
+		if t.owner != s.master.UUID {
+			continue
+		}
+		out = append(out, routefusion.TransactionResponse{
+			UUID:                t.UUID,
+			UserID:              t.UserID,
+			BeneficiaryID:       t.BeneficiaryID,
+			CurrencyPairs:       t.CurrencyPairs,
+			SourceCurrency:      t.SourceCurrency,
+			SourceAmount:        t.SourceAmount,
+			DestinationAmount:   t.DestinationAmount,
+			DestinationCurrency: t.DestinationCurrency,
+			ExchangeRate:        t.ExchangeRate,
+			AuthorizingIP:       t.AuthorizingIP,
+			State:               t.State,
+			TransferStates:      t.TransferStates,
+			CreatedAt:           t.CreatedAt,
+		})
+	}
+	start, end := paginate(r.query, len(out))
+	return http.StatusOK, out[start:end]
+}
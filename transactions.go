@@ -0,0 +1,19 @@
+package routefusion
+
+import "context"
+
+// GetTransactions lists a page of the transactions of the authenticated user.
+// The page is selected by opts, nil selects the first one.
+func (a *API) GetTransactions(opts *ListOptions) ([]TransactionResponse, error) {
+	return a.GetTransactionsWithContext(context.Background(), opts)
+}
+
+// GetTransactionsWithContext is like GetTransactions but binds the request to ctx.
+func (a *API) GetTransactionsWithContext(ctx context.Context,
+	opts *ListOptions) ([]TransactionResponse, error) {
+	var out []TransactionResponse
+	if _, err := a.list(ctx, getTransactionsEndpoint.operation(), opts, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,128 @@
+package routefusion
+
+import "context"
+
+// cancelledTransfer is the response to a transfer cancellation.
+type cancelledTransfer struct {
+	UUID string `json:"uuid"`
+}
+
+// CreateTransfer creates a transfer for the authenticated user.
+func (a *API) CreateTransfer(body *TransferInput) (*TransferResponse, error) {
+	return a.CreateTransferWithContext(context.Background(), body)
+}
+
+// CreateTransferWithContext is like CreateTransfer but binds the request to
+// ctx.
+func (a *API) CreateTransferWithContext(ctx context.Context,
+	body *TransferInput) (*TransferResponse, error) {
+	out := &TransferResponse{}
+	if err := a.do(ctx, createTransferEndpoint.operation(), body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTransfer returns a transfer of the authenticated user.
+func (a *API) GetTransfer(id string) (*TransferResponse, error) {
+	return a.GetTransferWithContext(context.Background(), id)
+}
+
+// GetTransferWithContext is like GetTransfer but binds the request to ctx.
+func (a *API) GetTransferWithContext(ctx context.Context,
+	id string) (*TransferResponse, error) {
+	out := &TransferResponse{}
+	if err := a.do(ctx, getTransferEndpoint.operation(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CancelTransfer cancels a transfer of the authenticated user and returns the
+// ID of the cancelled transfer.
+func (a *API) CancelTransfer(uuid string) (string, error) {
+	return a.CancelTransferWithContext(context.Background(), uuid)
+}
+
+// CancelTransferWithContext is like CancelTransfer but binds the request to
+// ctx.
+func (a *API) CancelTransferWithContext(ctx context.Context,
+	uuid string) (string, error) {
+	out := &cancelledTransfer{}
+	if err := a.do(ctx, cancelTransferEndpoint.operation(uuid), nil, out); err != nil {
+		return "", err
+	}
+	return out.UUID, nil
+}
+
+// CreateTransferMaster creates a transfer on behalf of a sub user.
+func (a *API) CreateTransferMaster(subUserID string, body *TransferInput) (*TransferState, error) {
+	return a.CreateTransferMasterWithContext(context.Background(), subUserID, body)
+}
+
+// CreateTransferMasterWithContext is like CreateTransferMaster but binds the
+// request to ctx.
+func (a *API) CreateTransferMasterWithContext(ctx context.Context,
+	subUserID string, body *TransferInput) (*TransferState, error) {
+	out := &TransferState{}
+	op := createTransferMasterEndpoint.operation(subUserID)
+	if err := a.do(ctx, op, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTransferMaster returns a transfer of a sub user.
+func (a *API) GetTransferMaster(subUserID, transferID string) (*TransferResponse, error) {
+	return a.GetTransferMasterWithContext(context.Background(), subUserID, transferID)
+}
+
+// GetTransferMasterWithContext is like GetTransferMaster but binds the request
+// to ctx.
+func (a *API) GetTransferMasterWithContext(ctx context.Context,
+	subUserID, transferID string) (*TransferResponse, error) {
+	out := &TransferResponse{}
+	op := getTransferMasterEndpoint.operation(subUserID, transferID)
+	if err := a.do(ctx, op, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTransferStatusMaster returns the current state of a transfer of a sub
+// user.
+func (a *API) GetTransferStatusMaster(subUserID, transferID string) (*TransferState, error) {
+	return a.GetTransferStatusMasterWithContext(context.Background(),
+		subUserID, transferID)
+}
+
+// GetTransferStatusMasterWithContext is like GetTransferStatusMaster but binds
+// the request to ctx.
+func (a *API) GetTransferStatusMasterWithContext(ctx context.Context,
+	subUserID, transferID string) (*TransferState, error) {
+	out := &TransferState{}
+	op := getTransferStatusMasterEndpoint.operation(subUserID, transferID)
+	if err := a.do(ctx, op, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CancelTransferMaster cancels a transfer of a sub user and returns the ID of
+// the cancelled transfer.
+func (a *API) CancelTransferMaster(subUserID, transferID string) (string, error) {
+	return a.CancelTransferMasterWithContext(context.Background(),
+		subUserID, transferID)
+}
+
+// CancelTransferMasterWithContext is like CancelTransferMaster but binds the
+// request to ctx.
+func (a *API) CancelTransferMasterWithContext(ctx context.Context,
+	subUserID, transferID string) (string, error) {
+	out := &cancelledTransfer{}
+	op := cancelTransferMasterEndpoint.operation(subUserID, transferID)
+	if err := a.do(ctx, op, nil, out); err != nil {
+		return "", err
+	}
+	return out.UUID, nil
+}
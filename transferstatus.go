@@ -0,0 +1,115 @@
+package routefusion
+
+import (
+	"sort"
+	"time"
+)
+
+// TransferStatus is the state of a transfer in its lifecycle.
+type TransferStatus string
+
+// Transfer states. Transfers are created, may wait for funds or compliance
+// checks, are processed and sent to the beneficiary bank, and end up
+// completed, failed, cancelled or returned by the beneficiary bank. The
+// beneficiary bank can still return a completed transfer.
+const (
+	TransferCreated    TransferStatus = "created"
+	TransferPending    TransferStatus = "pending"
+	TransferVerifying  TransferStatus = "verifying"
+	TransferProcessing TransferStatus = "processing"
+	TransferSent       TransferStatus = "sent"
+	TransferCompleted  TransferStatus = "completed"
+	TransferFailed     TransferStatus = "failed"
+	TransferCancelled  TransferStatus = "cancelled"
+	TransferReturned   TransferStatus = "returned"
+)
+
+// transferTransitions lists the states a transfer can move to from each
+// state it can leave.
+var transferTransitions = map[TransferStatus][]TransferStatus{
+	TransferCreated: {TransferPending, TransferVerifying, TransferProcessing,
+		TransferCancelled, TransferFailed},
+	TransferPending:    {TransferVerifying, TransferProcessing, TransferCancelled, TransferFailed},
+	TransferVerifying:  {TransferProcessing, TransferCancelled, TransferFailed},
+	TransferProcessing: {TransferSent, TransferCompleted, TransferFailed},
+	TransferSent:       {TransferCompleted, TransferFailed, TransferReturned},
+	TransferCompleted:  {TransferReturned},
+}
+
+// IsValid reports whether s is a known transfer state.
+func (s TransferStatus) IsValid() bool {
+	if _, ok := transferTransitions[s]; ok {
+		return true
+	}
+	return s.IsTerminal()
+}
+
+// IsTerminal reports whether s is a final state, from which the transfer
+// is not processed anymore. Completed transfers are terminal although they
+// can still be returned, see CanTransitionTo.
+func (s TransferStatus) IsTerminal() bool {
+	switch s {
+	case TransferCompleted, TransferFailed, TransferCancelled, TransferReturned:
+		return true
+	}
+	return false
+}
+
+// IsFailed reports whether the transfer did not reach the beneficiary,
+// either because it failed or because it was returned. Cancelled transfers
+// are not failed.
+func (s TransferStatus) IsFailed() bool {
+	return s == TransferFailed || s == TransferReturned
+}
+
+// CanTransitionTo reports whether a transfer in state s can move to next.
+// New transfers, whose state is empty, can only be created.
+func (s TransferStatus) CanTransitionTo(next TransferStatus) bool {
+	if s == "" {
+		return next == TransferCreated
+	}
+	for _, state := range transferTransitions[s] {
+		if state == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransferHistoryEntry is a state a transfer went through.
+type TransferHistoryEntry struct {
+	Status    TransferStatus
+	EnteredAt time.Time
+
+	// Duration is the time the transfer spent in Status before moving to
+	// the next state. It is zero for the current state.
+	Duration time.Duration
+}
+
+// History returns the states the transfer went through, oldest first.
+func (t *TransferResponse) History() []TransferHistoryEntry {
+	return transferHistory(t.TransferStates)
+}
+
+// History returns the states the transaction went through, oldest first.
+func (t *TransactionResponse) History() []TransferHistoryEntry {
+	return transferHistory(t.TransferStates)
+}
+
+func transferHistory(states []TransferState) []TransferHistoryEntry {
+	if len(states) == 0 {
+		return nil
+	}
+
+	history := make([]TransferHistoryEntry, len(states))
+	for i, s := range states {
+		history[i] = TransferHistoryEntry{Status: s.State, EnteredAt: s.CreatedAt}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].EnteredAt.Before(history[j].EnteredAt)
+	})
+	for i := 0; i < len(history)-1; i++ {
+		history[i].Duration = history[i+1].EnteredAt.Sub(history[i].EnteredAt)
+	}
+	return history
+}
@@ -0,0 +1,93 @@
+package routefusion
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransferStatus(t *testing.T) {
+	testCases := []struct {
+		status     TransferStatus
+		valid      bool
+		isTerminal bool
+		isFailed   bool
+	}{
+		{status: TransferCreated, valid: true},
+		{status: TransferPending, valid: true},
+		{status: TransferVerifying, valid: true},
+		{status: TransferProcessing, valid: true},
+		{status: TransferSent, valid: true},
+		{status: TransferCompleted, valid: true, isTerminal: true},
+		{status: TransferFailed, valid: true, isTerminal: true, isFailed: true},
+		{status: TransferCancelled, valid: true, isTerminal: true},
+		{status: TransferReturned, valid: true, isTerminal: true, isFailed: true},
+		{status: "on_hold"},
+		{status: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.status), func(t *testing.T) {
+			assert.Equal(t, testCase.valid, testCase.status.IsValid())
+			assert.Equal(t, testCase.isTerminal, testCase.status.IsTerminal())
+			assert.Equal(t, testCase.isFailed, testCase.status.IsFailed())
+		})
+	}
+}
+
+func Test_TransferStatusCanTransitionTo(t *testing.T) {
+	testCases := []struct {
+		from     TransferStatus
+		to       TransferStatus
+		expected bool
+	}{
+		{from: "", to: TransferCreated, expected: true},
+		{from: "", to: TransferProcessing},
+		{from: TransferCreated, to: TransferProcessing, expected: true},
+		{from: TransferCreated, to: TransferCancelled, expected: true},
+		{from: TransferPending, to: TransferVerifying, expected: true},
+		{from: TransferProcessing, to: TransferCancelled},
+		{from: TransferProcessing, to: TransferCompleted, expected: true},
+		{from: TransferSent, to: TransferReturned, expected: true},
+		{from: TransferCompleted, to: TransferReturned, expected: true},
+		{from: TransferCompleted, to: TransferFailed},
+		{from: TransferFailed, to: TransferProcessing},
+		{from: TransferProcessing, to: TransferCreated},
+		{from: "on_hold", to: TransferProcessing},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.from)+" to "+string(testCase.to), func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.from.CanTransitionTo(testCase.to))
+		})
+	}
+}
+
+func Test_TransferResponseHistory(t *testing.T) {
+	transfer := &TransferResponse{}
+	err := json.Unmarshal([]byte(`{"state": "completed", "transfer_states": [
+		{"state": "processing", "created_at": "2020-01-01T10:05:00Z"},
+		{"state": "created", "created_at": "2020-01-01T10:00:00Z"},
+		{"state": "completed", "created_at": "2020-01-02T10:05:00Z"}
+	]}`), transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(s string) time.Time {
+		parsed, _ := time.Parse(time.RFC3339, s)
+		return parsed
+	}
+	assert.Equal(t, TransferCompleted, transfer.State)
+	assert.Equal(t, []TransferHistoryEntry{
+		{Status: TransferCreated, EnteredAt: at("2020-01-01T10:00:00Z"),
+			Duration: 5 * time.Minute},
+		{Status: TransferProcessing, EnteredAt: at("2020-01-01T10:05:00Z"),
+			Duration: 24 * time.Hour},
+		{Status: TransferCompleted, EnteredAt: at("2020-01-02T10:05:00Z")},
+	}, transfer.History())
+
+	assert.Nil(t, (&TransactionResponse{}).History())
+}
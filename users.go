@@ -0,0 +1,63 @@
+package routefusion
+
+import "context"
+
+// GetUser returns the details of the authenticated user.
+func (a *API) GetUser() (*UserDetails, error) {
+	return a.GetUserWithContext(context.Background())
+}
+
+// GetUserWithContext is like GetUser but binds the request to ctx.
+func (a *API) GetUserWithContext(ctx context.Context) (*UserDetails, error) {
+	out := &UserDetails{}
+	if err := a.do(ctx, getUserEndpoint.operation(), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateUser updates the details of the authenticated user.
+func (a *API) UpdateUser(user *User) (*UpdatedUserDetails, error) {
+	return a.UpdateUserWithContext(context.Background(), user)
+}
+
+// UpdateUserWithContext is like UpdateUser but binds the request to ctx.
+func (a *API) UpdateUserWithContext(ctx context.Context,
+	user *User) (*UpdatedUserDetails, error) {
+	out := &UpdatedUserDetails{}
+	if err := a.do(ctx, updateUserEndpoint.operation(), user, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUserMaster returns the details of a sub user of the master account.
+func (a *API) GetUserMaster(subUserUUID string) (*AllUserDetails, error) {
+	return a.GetUserMasterWithContext(context.Background(), subUserUUID)
+}
+
+// GetUserMasterWithContext is like GetUserMaster but binds the request to ctx.
+func (a *API) GetUserMasterWithContext(ctx context.Context,
+	subUserUUID string) (*AllUserDetails, error) {
+	out := &AllUserDetails{}
+	if err := a.do(ctx, getUserMasterEndpoint.operation(subUserUUID), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListUsersMaster lists a page of the sub users of the master account.
+// The page is selected by opts, nil selects the first one.
+func (a *API) ListUsersMaster(opts *ListOptions) ([]AllUserDetails, error) {
+	return a.ListUsersMasterWithContext(context.Background(), opts)
+}
+
+// ListUsersMasterWithContext is like ListUsersMaster but binds the request to ctx.
+func (a *API) ListUsersMasterWithContext(ctx context.Context,
+	opts *ListOptions) ([]AllUserDetails, error) {
+	var out []AllUserDetails
+	if _, err := a.list(ctx, listUsersMasterEndpoint.operation(), opts, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
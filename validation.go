@@ -0,0 +1,148 @@
+package routefusion
+
+import (
+	"strings"
+
+	"github.com/routefusion/routefusion-golang/client"
+)
+
+// ValidationError is returned when an input fails client side validation.
+type ValidationError struct {
+	// Fields are the failures, named after the JSON fields of the input like
+	// the field errors of the API.
+	Fields []client.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	failures := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		failures[i] = f.Field + " " + f.Message
+	}
+	return "invalid input: " + strings.Join(failures, ", ")
+}
+
+// validator collects the field errors of an input.
+type validator struct {
+	fields []client.FieldError
+}
+
+func (v *validator) fail(field, message string) {
+	v.fields = append(v.fields, client.FieldError{Field: field, Message: message})
+}
+
+// require fails field if value is blank and reports whether it is set.
+func (v *validator) require(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.fail(field, "is required")
+		return false
+	}
+	return true
+}
+
+// check fails field with message if value is set but not valid.
+func (v *validator) check(field, value string, valid bool, message string) {
+	if strings.TrimSpace(value) != "" && !valid {
+		v.fail(field, message)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// Beneficiary types.
+const (
+	BeneficiaryPersonal = "personal"
+	BeneficiaryBusiness = "business"
+)
+
+// beneficiaryCountryFields are the fields beneficiaries need, besides the
+// ones every beneficiary needs, by bank country. Banks of the countries
+// using IBANs need account_number to be an IBAN and swift_bic, and so do
+// banks of countries that are not listed.
+var beneficiaryCountryFields = map[string][]string{
+	"AU": {"account_number", "bsb_number"},
+	"BR": {"account_number", "bank_code", "branch_code", "cpfcnpj"},
+	"CA": {"account_number", "bank_code", "branch_code"},
+	"IN": {"account_number", "bank_code"},
+	"MX": {"clabe"},
+	"US": {"account_number", "routing_number"},
+}
+
+// field returns the value of the field of b with the given JSON name.
+func (b *BeneficiaryInput) field(name string) string {
+	switch name {
+	case "account_number":
+		return b.AccountNumber
+	case "routing_number":
+		return b.RoutingNumber
+	case "swift_bic":
+		return b.SwiftBic
+	case "bsb_number":
+		return b.BsbNumber
+	case "cpfcnpj":
+		return b.Cpfcnpj
+	case "clabe":
+		return b.Clabe
+	case "bank_code":
+		return b.BankCode
+	case "branch_code":
+		return b.BranchCode
+	}
+	return ""
+}
+
+// Validate checks b before it is sent to the API. It returns a
+// *ValidationError listing the missing fields, which depend on the type and
+// the bank country of the beneficiary, and the fields whose format or check
+// digits are wrong.
+func (b *BeneficiaryInput) Validate() error {
+	v := &validator{}
+
+	switch b.Type {
+	case BeneficiaryPersonal:
+		v.require("first_name_on_account", b.FirstNameOnAccount)
+		v.require("last_name_on_account", b.LastNameOnAccount)
+	case BeneficiaryBusiness:
+		v.require("company_name", b.CompanyName)
+	case "":
+		v.fail("type", "is required")
+	default:
+		v.fail("type", `must be "personal" or "business"`)
+	}
+
+	if v.require("currency", b.Currency) && len(b.Currency) != 3 {
+		v.fail("currency", "must be an ISO 4217 code")
+	}
+	v.require("bank_name", b.BankName)
+	country := strings.ToUpper(b.BankCountry)
+	if v.require("bank_country", b.BankCountry) && len(country) != 2 {
+		v.fail("bank_country", "must be an ISO 3166-1 alpha-2 code")
+		return v.err()
+	}
+
+	required, ok := beneficiaryCountryFields[country]
+	if !ok {
+		required = []string{"account_number", "swift_bic"}
+	}
+	for _, name := range required {
+		v.require(name, b.field(name))
+	}
+
+	if usesIBAN(country) {
+		v.check("account_number", b.AccountNumber, validIBAN(b.AccountNumber, country),
+			"is not a valid IBAN")
+	}
+	if country == "US" {
+		v.check("routing_number", b.RoutingNumber, validABA(b.RoutingNumber),
+			"is not a valid ABA routing number")
+	}
+	v.check("clabe", b.Clabe, validCLABE(b.Clabe), "is not a valid CLABE")
+	v.check("cpfcnpj", b.Cpfcnpj, validCPFOrCNPJ(b.Cpfcnpj), "is not a valid CPF or CNPJ")
+	v.check("bsb_number", b.BsbNumber, validBSB(b.BsbNumber), "is not a valid BSB number")
+	v.check("swift_bic", b.SwiftBic, validSwiftBic(b.SwiftBic), "is not a valid SWIFT/BIC code")
+	return v.err()
+}
@@ -0,0 +1,189 @@
+package routefusion
+
+import (
+	"testing"
+
+	"github.com/routefusion/routefusion-golang/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Checksums(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		valid    func(string) bool
+		value    string
+		expected bool
+	}{
+		{desc: "IBAN", valid: func(s string) bool { return validIBAN(s, "DE") },
+			value: "DE89370400440532013000", expected: true},
+		{desc: "IBAN with spaces", valid: func(s string) bool { return validIBAN(s, "GB") },
+			value: "gb82 west 1234 5698 7654 32", expected: true},
+		{desc: "IBAN check digits", valid: func(s string) bool { return validIBAN(s, "DE") },
+			value: "DE88370400440532013000"},
+		{desc: "IBAN length", valid: func(s string) bool { return validIBAN(s, "DE") },
+			value: "DE8937040044053201300"},
+		{desc: "IBAN of another country", valid: func(s string) bool { return validIBAN(s, "FR") },
+			value: "DE89370400440532013000"},
+		{desc: "ABA", valid: validABA, value: "021000021", expected: true},
+		{desc: "ABA with separators", valid: validABA, value: "0210-0002 1", expected: true},
+		{desc: "ABA checksum", valid: validABA, value: "021000022"},
+		{desc: "ABA length", valid: validABA, value: "02100002"},
+		{desc: "CLABE", valid: validCLABE, value: "032180000118359719", expected: true},
+		{desc: "CLABE check digit", valid: validCLABE, value: "032180000118359718"},
+		{desc: "CLABE letters", valid: validCLABE, value: "03218000011835971A"},
+		{desc: "CPF", valid: validCPFOrCNPJ, value: "529.982.247-25", expected: true},
+		{desc: "CPF check digits", valid: validCPFOrCNPJ, value: "529.982.247-26"},
+		{desc: "CPF repeated digits", valid: validCPFOrCNPJ, value: "111.111.111-11"},
+		{desc: "CNPJ", valid: validCPFOrCNPJ, value: "11.222.333/0001-81", expected: true},
+		{desc: "CNPJ check digits", valid: validCPFOrCNPJ, value: "11.222.333/0001-82"},
+		{desc: "CPF or CNPJ length", valid: validCPFOrCNPJ, value: "1234567890"},
+		{desc: "BSB", valid: validBSB, value: "062-000", expected: true},
+		{desc: "BSB without hyphen", valid: validBSB, value: "062000", expected: true},
+		{desc: "BSB with space", valid: validBSB, value: "062 000", expected: true},
+		{desc: "BSB letters", valid: validBSB, value: "062-00A"},
+		{desc: "BSB length", valid: validBSB, value: "06200"},
+		{desc: "BIC", valid: validSwiftBic, value: "DEUTDEFF", expected: true},
+		{desc: "BIC with branch", valid: validSwiftBic, value: "deutdeff500", expected: true},
+		{desc: "BIC length", valid: validSwiftBic, value: "DEUTDEFF5"},
+		{desc: "BIC digits in bank code", valid: validSwiftBic, value: "D3UTDEFF"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.valid(testCase.value))
+		})
+	}
+}
+
+func Test_BeneficiaryInputValidate(t *testing.T) {
+	personal := func(country, currency string) BeneficiaryInput {
+		return BeneficiaryInput{Type: BeneficiaryPersonal, FirstNameOnAccount: "Ana",
+			LastNameOnAccount: "Diaz", BankName: "Bank", BankCountry: country,
+			Currency: currency}
+	}
+	with := func(b BeneficiaryInput, set func(*BeneficiaryInput)) BeneficiaryInput {
+		set(&b)
+		return b
+	}
+
+	testCases := []struct {
+		desc     string
+		input    BeneficiaryInput
+		expected []client.FieldError
+	}{
+		{
+			desc: "US",
+			input: with(personal("US", "USD"), func(b *BeneficiaryInput) {
+				b.AccountNumber = "123456789"
+				b.RoutingNumber = "021000021"
+			}),
+		},
+		{
+			desc: "Mexico",
+			input: with(personal("mx", "MXN"), func(b *BeneficiaryInput) {
+				b.Clabe = "032180000118359719"
+			}),
+		},
+		{
+			desc: "Brazil",
+			input: with(personal("BR", "BRL"), func(b *BeneficiaryInput) {
+				b.AccountNumber = "12345"
+				b.BankCode = "001"
+				b.BranchCode = "1234"
+				b.Cpfcnpj = "52998224725"
+			}),
+		},
+		{
+			desc: "IBAN country",
+			input: BeneficiaryInput{Type: BeneficiaryBusiness, CompanyName: "Acme",
+				BankName: "Bank", BankCountry: "DE", Currency: "EUR",
+				AccountNumber: "DE89370400440532013000", SwiftBic: "DEUTDEFF"},
+		},
+		{
+			desc:  "missing common fields",
+			input: BeneficiaryInput{},
+			expected: []client.FieldError{
+				{Field: "type", Message: "is required"},
+				{Field: "currency", Message: "is required"},
+				{Field: "bank_name", Message: "is required"},
+				{Field: "bank_country", Message: "is required"},
+				{Field: "account_number", Message: "is required"},
+				{Field: "swift_bic", Message: "is required"},
+			},
+		},
+		{
+			desc: "missing fields of the type",
+			input: BeneficiaryInput{Type: BeneficiaryBusiness, BankName: "Bank",
+				BankCountry: "MX", Currency: "MXN", Clabe: "032180000118359719"},
+			expected: []client.FieldError{{Field: "company_name", Message: "is required"}},
+		},
+		{
+			desc:  "missing fields of the country",
+			input: personal("AU", "AUD"),
+			expected: []client.FieldError{
+				{Field: "account_number", Message: "is required"},
+				{Field: "bsb_number", Message: "is required"},
+			},
+		},
+		{
+			desc: "invalid codes",
+			input: with(personal("USA", "US"), func(b *BeneficiaryInput) {
+				b.Type = "trust"
+			}),
+			expected: []client.FieldError{
+				{Field: "type", Message: `must be "personal" or "business"`},
+				{Field: "currency", Message: "must be an ISO 4217 code"},
+				{Field: "bank_country", Message: "must be an ISO 3166-1 alpha-2 code"},
+			},
+		},
+		{
+			desc: "invalid check digits",
+			input: with(personal("US", "USD"), func(b *BeneficiaryInput) {
+				b.AccountNumber = "123456789"
+				b.RoutingNumber = "021000022"
+				b.Clabe = "032180000118359718"
+				b.Cpfcnpj = "11.222.333/0001-82"
+				b.BsbNumber = "06-200"
+				b.SwiftBic = "CHASUS"
+			}),
+			expected: []client.FieldError{
+				{Field: "routing_number", Message: "is not a valid ABA routing number"},
+				{Field: "clabe", Message: "is not a valid CLABE"},
+				{Field: "cpfcnpj", Message: "is not a valid CPF or CNPJ"},
+				{Field: "bsb_number", Message: "is not a valid BSB number"},
+				{Field: "swift_bic", Message: "is not a valid SWIFT/BIC code"},
+			},
+		},
+		{
+			desc: "account number is not an IBAN",
+			input: with(personal("FR", "EUR"), func(b *BeneficiaryInput) {
+				b.AccountNumber = "123456789"
+				b.SwiftBic = "BNPAFRPP"
+			}),
+			expected: []client.FieldError{
+				{Field: "account_number", Message: "is not a valid IBAN"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			err := testCase.input.Validate()
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.IsType(t, &ValidationError{}, err) {
+				assert.Equal(t, testCase.expected, err.(*ValidationError).Fields)
+			}
+		})
+	}
+}
+
+func Test_ValidationErrorMessage(t *testing.T) {
+	err := &ValidationError{Fields: []client.FieldError{
+		{Field: "clabe", Message: "is required"},
+		{Field: "type", Message: "is required"},
+	}}
+	assert.Equal(t, "invalid input: clabe is required, type is required", err.Error())
+}
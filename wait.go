@@ -0,0 +1,225 @@
+package routefusion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Default polling intervals of WaitForTransfer.
+const (
+	defaultWaitInitialInterval = time.Second
+	defaultWaitMaxInterval     = 30 * time.Second
+	defaultWaitMultiplier      = 2
+)
+
+// WaitOptions configures WaitForTransfer. The zero value waits for a
+// terminal state, polling every second and backing off up to every thirty
+// seconds, for as long as the context allows.
+type WaitOptions struct {
+	// Targets are states that end the wait in addition to the terminal
+	// ones, e.g. TransferSent.
+	Targets []TransferStatus
+
+	// SubUserID, when set, waits for a transfer of this sub user.
+	SubUserID string
+
+	// Timeout bounds the wait in addition to the deadline of the context.
+	Timeout time.Duration
+
+	// InitialInterval is the delay between polls after a state change.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between polls.
+	MaxInterval time.Duration
+
+	// Multiplier grows the delay after every poll that saw no change.
+	Multiplier float64
+
+	// OnState, when set, is called with the transfer every time its state
+	// is seen to change, starting with the state found by the first poll.
+	OnState func(t *TransferResponse)
+
+	// States, when set, receives the transfer like OnState does. Sends
+	// block until the value is received or the wait ends.
+	States chan<- *TransferResponse
+}
+
+func (o *WaitOptions) withDefaults() WaitOptions {
+	var opts WaitOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = defaultWaitInitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaultWaitMaxInterval
+	}
+	if opts.MaxInterval < opts.InitialInterval {
+		opts.MaxInterval = opts.InitialInterval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaultWaitMultiplier
+	}
+	return opts
+}
+
+func (o *WaitOptions) done(state TransferStatus) bool {
+	if state.IsTerminal() {
+		return true
+	}
+	for _, target := range o.Targets {
+		if state == target {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitTimeoutError is returned by WaitForTransfer when the transfer did not
+// reach the awaited states in time.
+type WaitTimeoutError struct {
+	// TransferID is the transfer waited for.
+	TransferID string
+
+	// Last is the last version of the transfer seen, nil if no poll
+	// succeeded.
+	Last *TransferResponse
+
+	// Waited is how long the wait lasted.
+	Waited time.Duration
+
+	err error
+}
+
+func (e *WaitTimeoutError) Error() string {
+	state := TransferStatus("unknown")
+	if e.Last != nil {
+		state = e.Last.State
+	}
+	return fmt.Sprintf("timed out after %s waiting for transfer %s, last state: %s",
+		e.Waited.Round(time.Millisecond), e.TransferID, state)
+}
+
+// Timeout reports that the error is a timeout.
+func (e *WaitTimeoutError) Timeout() bool {
+	return true
+}
+
+// Unwrap returns the context error that ended the wait.
+func (e *WaitTimeoutError) Unwrap() error {
+	return e.err
+}
+
+// WaitForTransfer polls a transfer until it reaches a terminal state or one
+// of the target states of opts, and returns it. nil opts selects the
+// defaults.
+//
+// The wait ends with a *WaitTimeoutError when the timeout of opts or the
+// deadline of ctx is reached, with the error of ctx when ctx is cancelled,
+// and with the error of the poll when one fails after the client retries.
+func (a *API) WaitForTransfer(ctx context.Context, id string,
+	opts *WaitOptions) (*TransferResponse, error) {
+	o := opts.withDefaults()
+	start := time.Now()
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	var last *TransferResponse
+	b := &backoff{initial: o.InitialInterval, max: o.MaxInterval, multiplier: o.Multiplier}
+	for {
+		t, err := a.getTransfer(ctx, o.SubUserID, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, waitError(ctx, id, last, start)
+			}
+			return nil, err
+		}
+
+		changed := last == nil || t.State != last.State
+		if changed {
+			if err := o.notify(ctx, t); err != nil {
+				return nil, waitError(ctx, id, t, start)
+			}
+		}
+		last = t
+
+		if o.done(t.State) {
+			return t, nil
+		}
+		if !b.sleep(ctx, changed) {
+			return nil, waitError(ctx, id, last, start)
+		}
+	}
+}
+
+// backoff paces the polls of a wait. The delay starts over from initial
+// after every change and grows by multiplier up to max otherwise.
+type backoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	interval   time.Duration
+}
+
+// sleep waits before the next poll and reports whether ctx allows it.
+func (b *backoff) sleep(ctx context.Context, changed bool) bool {
+	if changed || b.interval == 0 {
+		b.interval = b.initial
+	} else {
+		b.interval = time.Duration(float64(b.interval) * b.multiplier)
+		if b.interval > b.max {
+			b.interval = b.max
+		}
+	}
+
+	timer := time.NewTimer(b.interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (a *API) getTransfer(ctx context.Context, subUserID,
+	id string) (*TransferResponse, error) {
+	if subUserID != "" {
+		return a.GetTransferMasterWithContext(ctx, subUserID, id)
+	}
+	return a.GetTransferWithContext(ctx, id)
+}
+
+func (o *WaitOptions) notify(ctx context.Context, t *TransferResponse) error {
+	if o.OnState != nil {
+		o.OnState(t)
+	}
+	if o.States != nil {
+		select {
+		case o.States <- t:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// waitError returns the error ending a wait whose context is done.
+func waitError(ctx context.Context, id string, last *TransferResponse,
+	start time.Time) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx.Err()
+	}
+	return &WaitTimeoutError{
+		TransferID: id,
+		Last:       last,
+		Waited:     time.Since(start),
+		err:        ctx.Err(),
+	}
+}
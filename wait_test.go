@@ -0,0 +1,185 @@
+package routefusion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/routefusion/routefusion-golang/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedTransfer answers with the given states one poll after the other,
+// repeating the last one, and records the polled paths.
+func scriptedTransfer(states []TransferStatus, paths *[]string) http.HandlerFunc {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		*paths = append(*paths, r.URL.Path)
+		state := states[len(states)-1]
+		if len(*paths) <= len(states) {
+			state = states[len(*paths)-1]
+		}
+		fmt.Fprintf(w, `{"uuid": "transfer-1", "state": %q}`, state)
+	}
+}
+
+func Test_WaitForTransfer(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		states         []TransferStatus
+		opts           WaitOptions
+		expectedState  TransferStatus
+		expectedSeen   []TransferStatus
+		expectedPolls  int
+		expectedPath   string
+		expectedErrMsg string
+	}{
+		{
+			desc: "until a terminal state",
+			states: []TransferStatus{TransferCreated, TransferCreated, TransferProcessing,
+				TransferProcessing, TransferCompleted},
+			expectedState: TransferCompleted,
+			expectedSeen:  []TransferStatus{TransferCreated, TransferProcessing, TransferCompleted},
+			expectedPolls: 5,
+			expectedPath:  "/v1/transfers/transfer-1",
+		},
+		{
+			desc:          "until a target state",
+			states:        []TransferStatus{TransferProcessing, TransferSent, TransferCompleted},
+			opts:          WaitOptions{Targets: []TransferStatus{TransferSent}},
+			expectedState: TransferSent,
+			expectedSeen:  []TransferStatus{TransferProcessing, TransferSent},
+			expectedPolls: 2,
+			expectedPath:  "/v1/transfers/transfer-1",
+		},
+		{
+			desc:          "already terminal",
+			states:        []TransferStatus{TransferCancelled},
+			expectedState: TransferCancelled,
+			expectedSeen:  []TransferStatus{TransferCancelled},
+			expectedPolls: 1,
+			expectedPath:  "/v1/transfers/transfer-1",
+		},
+		{
+			desc:          "transfer of a sub user",
+			states:        []TransferStatus{TransferFailed},
+			opts:          WaitOptions{SubUserID: "sub-1"},
+			expectedState: TransferFailed,
+			expectedSeen:  []TransferStatus{TransferFailed},
+			expectedPolls: 1,
+			expectedPath:  "/v1/users/sub-1/transfers/transfer-1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			var paths []string
+			a, ts := newTestAPI(scriptedTransfer(testCase.states, &paths))
+			defer ts.Close()
+
+			var seen []TransferStatus
+			opts := testCase.opts
+			opts.InitialInterval = time.Millisecond
+			opts.OnState = func(t *TransferResponse) { seen = append(seen, t.State) }
+
+			transfer, err := a.WaitForTransfer(context.Background(), "transfer-1", &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, testCase.expectedState, transfer.State)
+			assert.Equal(t, testCase.expectedSeen, seen)
+			assert.Len(t, paths, testCase.expectedPolls)
+			assert.Equal(t, testCase.expectedPath, paths[0])
+		})
+	}
+}
+
+func Test_WaitForTransferChannel(t *testing.T) {
+	var paths []string
+	a, ts := newTestAPI(scriptedTransfer([]TransferStatus{TransferCreated,
+		TransferProcessing, TransferCompleted}, &paths))
+	defer ts.Close()
+
+	states := make(chan *TransferResponse)
+	var seen []TransferStatus
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for t := range states {
+			seen = append(seen, t.State)
+		}
+	}()
+
+	_, err := a.WaitForTransfer(context.Background(), "transfer-1",
+		&WaitOptions{InitialInterval: time.Millisecond, States: states})
+	close(states)
+	<-done
+
+	assert.NoError(t, err)
+	assert.Equal(t, []TransferStatus{TransferCreated, TransferProcessing,
+		TransferCompleted}, seen)
+}
+
+func Test_WaitForTransferTimeout(t *testing.T) {
+	var paths []string
+	a, ts := newTestAPI(scriptedTransfer([]TransferStatus{TransferProcessing}, &paths))
+	defer ts.Close()
+
+	transfer, err := a.WaitForTransfer(context.Background(), "transfer-1",
+		&WaitOptions{InitialInterval: time.Millisecond, Timeout: 30 * time.Millisecond})
+	assert.Nil(t, transfer)
+
+	timeoutErr, ok := err.(*WaitTimeoutError)
+	if !assert.True(t, ok, "unexpected error %v", err) {
+		return
+	}
+	assert.True(t, timeoutErr.Timeout())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "transfer-1", timeoutErr.TransferID)
+	assert.Equal(t, TransferProcessing, timeoutErr.Last.State)
+	assert.True(t, timeoutErr.Waited >= 30*time.Millisecond)
+	assert.Contains(t, err.Error(), "last state: processing")
+}
+
+func Test_WaitForTransferErrors(t *testing.T) {
+	var paths []string
+	a, ts := newTestAPI(scriptedTransfer([]TransferStatus{TransferProcessing}, &paths))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := a.WaitForTransfer(ctx, "transfer-1", nil)
+	assert.Equal(t, context.Canceled, err)
+
+	notFound, ts := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer ts.Close()
+	_, err = notFound.WaitForTransfer(context.Background(), "transfer-1", nil)
+	if assert.Error(t, err) {
+		assert.Equal(t, client.ErrCodeNotFound, err.(client.RFError).Code())
+	}
+}
+
+func Test_WaitOptionsDefaults(t *testing.T) {
+	var nilOpts *WaitOptions
+	assert.Equal(t, WaitOptions{
+		InitialInterval: time.Second,
+		MaxInterval:     30 * time.Second,
+		Multiplier:      2,
+	}, nilOpts.withDefaults())
+
+	opts := &WaitOptions{InitialInterval: time.Minute, Multiplier: 1.5}
+	assert.Equal(t, WaitOptions{
+		InitialInterval: time.Minute,
+		MaxInterval:     time.Minute,
+		Multiplier:      1.5,
+	}, opts.withDefaults())
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType identifies the kind of an event.
+type EventType string
+
+// Event types sent by Routefusion.
+const (
+	EventTransferStateChanged EventType = "transfer.state_changed"
+	EventBeneficiaryVerified  EventType = "beneficiary.verified"
+	EventKYCStatusChanged     EventType = "kyc.status_changed"
+	EventBatchCompleted       EventType = "batch.completed"
+)
+
+// Event is the envelope of every webhook delivery. Data holds the payload
+// specific to Type, decoded by the Handler into one of the typed events.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      EventType       `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// TransferStateChanged is the data of EventTransferStateChanged events.
+type TransferStateChanged struct {
+	TransferUUID  string    `json:"transfer_uuid"`
+	UserUUID      string    `json:"user_uuid"`
+	State         string    `json:"state"`
+	PreviousState string    `json:"previous_state"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// BeneficiaryVerified is the data of EventBeneficiaryVerified events.
+type BeneficiaryVerified struct {
+	BeneficiaryUUID string    `json:"beneficiary_uuid"`
+	UserUUID        string    `json:"user_uuid"`
+	Verified        bool      `json:"verified"`
+	Status          string    `json:"status"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// KYCStatusChanged is the data of EventKYCStatusChanged events.
+type KYCStatusChanged struct {
+	UserUUID  string    `json:"user_uuid"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BatchCompleted is the data of EventBatchCompleted events.
+type BatchCompleted struct {
+	BatchUUID   string    `json:"batch_uuid"`
+	Status      string    `json:"status"`
+	Succeeded   int       `json:"succeeded"`
+	Failed      int       `json:"failed"`
+	CompletedAt time.Time `json:"completed_at"`
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderSignature is the header Routefusion signs webhook deliveries with.
+// Its value has the form "t=<unix timestamp>,v1=<hex signature>" where the
+// signature is the HMAC-SHA256, keyed with the webhook secret, of the
+// timestamp, a dot and the raw request body. Several v1 entries may be
+// present while a secret is being rolled.
+const HeaderSignature = "X-Routefusion-Signature"
+
+const signatureScheme = "v1"
+
+// Verification errors returned by Verify.
+var (
+	ErrMissingSignature = errors.New("webhook: missing or malformed signature header")
+	ErrInvalidSignature = errors.New("webhook: signature does not match payload")
+	ErrTimestampExpired = errors.New("webhook: timestamp outside of tolerance")
+)
+
+// Sign returns the signature header value for payload sent at t. It is used
+// by Routefusion to sign deliveries and can be used to test handlers.
+func Sign(secret, payload []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + "," + signatureScheme + "=" + computeSignature(secret, ts, payload)
+}
+
+func computeSignature(secret []byte, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that header is a valid signature of payload by secret and
+// that it was made no more than tolerance away from now.
+func Verify(secret, payload []byte, header string, tolerance time.Duration,
+	now time.Time) error {
+	ts, signatures := parseSignatureHeader(header)
+	if ts == "" || len(signatures) == 0 {
+		return ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrMissingSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	expected := []byte(computeSignature(secret, ts, payload))
+	for _, s := range signatures {
+		if hmac.Equal(expected, []byte(s)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// parseSignatureHeader returns the timestamp and the signatures of the
+// supported scheme found in header.
+func parseSignatureHeader(header string) (string, []string) {
+	var ts string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case signatureScheme:
+			signatures = append(signatures, kv[1])
+		}
+	}
+	return ts, signatures
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Verify(t *testing.T) {
+	secret := []byte("whsec")
+	payload := []byte(`{"id": "evt-1"}`)
+	now := time.Unix(1700000000, 0)
+
+	testCases := []struct {
+		desc     string
+		header   string
+		expected error
+	}{
+		{
+			desc:   "valid signature",
+			header: Sign(secret, payload, now),
+		},
+		{
+			desc:   "timestamp within tolerance",
+			header: Sign(secret, payload, now.Add(-4*time.Minute)),
+		},
+		{
+			desc:   "one of several signatures matches",
+			header: Sign(secret, payload, now) + ",v1=deadbeef",
+		},
+		{
+			desc:     "missing header",
+			expected: ErrMissingSignature,
+		},
+		{
+			desc:     "missing timestamp",
+			header:   "v1=deadbeef",
+			expected: ErrMissingSignature,
+		},
+		{
+			desc:     "unsupported scheme only",
+			header:   "t=1700000000,v0=deadbeef",
+			expected: ErrMissingSignature,
+		},
+		{
+			desc:     "malformed timestamp",
+			header:   "t=yesterday,v1=deadbeef",
+			expected: ErrMissingSignature,
+		},
+		{
+			desc:     "wrong secret",
+			header:   Sign([]byte("other"), payload, now),
+			expected: ErrInvalidSignature,
+		},
+		{
+			desc:     "tampered payload",
+			header:   Sign(secret, []byte(`{"id": "evt-2"}`), now),
+			expected: ErrInvalidSignature,
+		},
+		{
+			desc:     "old timestamp",
+			header:   Sign(secret, payload, now.Add(-6*time.Minute)),
+			expected: ErrTimestampExpired,
+		},
+		{
+			desc:     "future timestamp",
+			header:   Sign(secret, payload, now.Add(6*time.Minute)),
+			expected: ErrTimestampExpired,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			err := Verify(secret, payload, testCase.header, 5*time.Minute, now)
+			assert.Equal(t, testCase.expected, err)
+		})
+	}
+}
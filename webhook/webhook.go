@@ -0,0 +1,309 @@
+// Package webhook receives the events Routefusion posts to registered
+// webhook URLs.
+//
+// A Handler verifies the signature and the timestamp of every delivery,
+// rejects replayed deliveries, decodes the event and dispatches it to the
+// callbacks registered for its type:
+//
+//	h, err := webhook.NewHandler(webhook.Config{Secret: secret})
+//	if err != nil {
+//		return err
+//	}
+//	h.OnTransferStateChanged(func(ctx context.Context, e *webhook.Event,
+//		data *webhook.TransferStateChanged) error {
+//		return markPaid(ctx, data.TransferUUID)
+//	})
+//	http.Handle("/routefusion/events", h)
+//
+// Deliveries whose callbacks fail are answered with a server error so that
+// Routefusion delivers them again.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTolerance is how far from the current time the timestamp of a
+	// delivery may be when Config.Tolerance is not set.
+	DefaultTolerance = 5 * time.Minute
+
+	// DefaultMaxBodyBytes is the largest payload accepted when
+	// Config.MaxBodyBytes is not set.
+	DefaultMaxBodyBytes = 1 << 20
+)
+
+var (
+	// ErrReplayed is the error reported for deliveries of an event that was
+	// already handled within the replay window.
+	ErrReplayed = errors.New("webhook: event already delivered")
+
+	// ErrMissingEventID is the error reported for events without an id,
+	// whose replays could not be detected.
+	ErrMissingEventID = errors.New("webhook: event has no id")
+
+	// ErrEmptySecret is returned by NewHandler when Config.Secret is empty,
+	// as anyone could sign deliveries with an empty key.
+	ErrEmptySecret = errors.New("webhook: empty secret")
+)
+
+// Config configures a Handler.
+type Config struct {
+	// Secret is the signing secret of the webhook. It is required.
+	Secret string
+
+	// Tolerance bounds how far from the current time delivery timestamps
+	// may be. Events are remembered for twice as long to reject replays, as
+	// a delivery signed up to Tolerance in the future stays valid for
+	// 2×Tolerance after it arrives.
+	Tolerance time.Duration
+
+	// MaxBodyBytes limits the size of payloads.
+	MaxBodyBytes int64
+
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// Handler is an http.Handler receiving Routefusion webhook deliveries. It is
+// safe for concurrent use once its callbacks are registered.
+type Handler struct {
+	secret       []byte
+	tolerance    time.Duration
+	maxBodyBytes int64
+	now          func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+
+	// deliveries are the remembered deliveries, oldest first, to expire them
+	// without going through seen.
+	deliveries []delivery
+
+	callbacks map[EventType][]func(context.Context, *Event) error
+	fallback  []func(context.Context, *Event) error
+}
+
+// delivery is the id and the time of a remembered delivery.
+type delivery struct {
+	id string
+	at time.Time
+}
+
+// NewHandler returns a Handler for the given configuration, or
+// ErrEmptySecret if it has no secret.
+func NewHandler(config Config) (*Handler, error) {
+	if config.Secret == "" {
+		return nil, ErrEmptySecret
+	}
+
+	h := &Handler{
+		secret:       []byte(config.Secret),
+		tolerance:    config.Tolerance,
+		maxBodyBytes: config.MaxBodyBytes,
+		now:          config.Now,
+		seen:         map[string]time.Time{},
+		callbacks:    map[EventType][]func(context.Context, *Event) error{},
+	}
+	if h.tolerance <= 0 {
+		h.tolerance = DefaultTolerance
+	}
+	if h.maxBodyBytes <= 0 {
+		h.maxBodyBytes = DefaultMaxBodyBytes
+	}
+	if h.now == nil {
+		h.now = time.Now
+	}
+	return h, nil
+}
+
+// On registers fn for events of type t. The raw event is passed, see the
+// typed registration methods to get decoded data.
+func (h *Handler) On(t EventType, fn func(ctx context.Context, e *Event) error) {
+	h.callbacks[t] = append(h.callbacks[t], fn)
+}
+
+// OnUnhandled registers fn for events no callback is registered for, such
+// as event types added to the API after this package.
+func (h *Handler) OnUnhandled(fn func(ctx context.Context, e *Event) error) {
+	h.fallback = append(h.fallback, fn)
+}
+
+// OnTransferStateChanged registers fn for EventTransferStateChanged events.
+func (h *Handler) OnTransferStateChanged(fn func(ctx context.Context, e *Event,
+	data *TransferStateChanged) error) {
+	h.On(EventTransferStateChanged, func(ctx context.Context, e *Event) error {
+		data := &TransferStateChanged{}
+		if err := decodeData(e, data); err != nil {
+			return err
+		}
+		return fn(ctx, e, data)
+	})
+}
+
+// OnBeneficiaryVerified registers fn for EventBeneficiaryVerified events.
+func (h *Handler) OnBeneficiaryVerified(fn func(ctx context.Context, e *Event,
+	data *BeneficiaryVerified) error) {
+	h.On(EventBeneficiaryVerified, func(ctx context.Context, e *Event) error {
+		data := &BeneficiaryVerified{}
+		if err := decodeData(e, data); err != nil {
+			return err
+		}
+		return fn(ctx, e, data)
+	})
+}
+
+// OnKYCStatusChanged registers fn for EventKYCStatusChanged events.
+func (h *Handler) OnKYCStatusChanged(fn func(ctx context.Context, e *Event,
+	data *KYCStatusChanged) error) {
+	h.On(EventKYCStatusChanged, func(ctx context.Context, e *Event) error {
+		data := &KYCStatusChanged{}
+		if err := decodeData(e, data); err != nil {
+			return err
+		}
+		return fn(ctx, e, data)
+	})
+}
+
+// OnBatchCompleted registers fn for EventBatchCompleted events.
+func (h *Handler) OnBatchCompleted(fn func(ctx context.Context, e *Event,
+	data *BatchCompleted) error) {
+	h.On(EventBatchCompleted, func(ctx context.Context, e *Event) error {
+		data := &BatchCompleted{}
+		if err := decodeData(e, data); err != nil {
+			return err
+		}
+		return fn(ctx, e, data)
+	})
+}
+
+// decodeError reports event data that does not match its type.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string {
+	return "webhook: invalid event data: " + e.err.Error()
+}
+
+func decodeData(e *Event, data interface{}) error {
+	if err := json.Unmarshal(e.Data, data); err != nil {
+		return &decodeError{err: err}
+	}
+	return nil
+}
+
+// ParseEvent verifies the signature header of payload and decodes the event,
+// which must have an id. It does not check for replays.
+func (h *Handler) ParseEvent(payload []byte, signature string) (*Event, error) {
+	if err := Verify(h.secret, payload, signature, h.tolerance, h.now()); err != nil {
+		return nil, err
+	}
+
+	e := &Event{}
+	if err := json.Unmarshal(payload, e); err != nil {
+		return nil, &decodeError{err: err}
+	}
+	if e.ID == "" {
+		return nil, ErrMissingEventID
+	}
+	return e, nil
+}
+
+// ServeHTTP verifies and dispatches a delivery.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed),
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		http.Error(w, "webhook: unreadable payload", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	e, err := h.ParseEvent(payload, r.Header.Get(HeaderSignature))
+	if err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+
+	if !h.remember(e.ID) {
+		http.Error(w, ErrReplayed.Error(), http.StatusConflict)
+		return
+	}
+	if err := h.dispatch(r.Context(), e); err != nil {
+		h.forget(e.ID)
+		http.Error(w, err.Error(), statusCode(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func statusCode(err error) int {
+	var decodeErr *decodeError
+	switch {
+	case errors.As(err, &decodeErr), errors.Is(err, ErrMissingEventID):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature),
+		errors.Is(err, ErrTimestampExpired):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *Handler) dispatch(ctx context.Context, e *Event) error {
+	callbacks := h.callbacks[e.Type]
+	if len(callbacks) == 0 {
+		callbacks = h.fallback
+	}
+	for _, fn := range callbacks {
+		if err := fn(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remember records the delivery of event id and reports whether it is the
+// first one within the replay window. Deliveries are forgotten, oldest
+// first, once their signature cannot be valid anymore: a delivery signed
+// up to tolerance in the future stays valid for 2×tolerance after it
+// arrives.
+func (h *Handler) remember(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	for len(h.deliveries) > 0 && now.Sub(h.deliveries[0].at) > 2*h.tolerance {
+		d := h.deliveries[0]
+		h.deliveries[0] = delivery{}
+		h.deliveries = h.deliveries[1:]
+		if at, ok := h.seen[d.id]; ok && at.Equal(d.at) {
+			delete(h.seen, d.id)
+		}
+	}
+	if _, ok := h.seen[id]; ok {
+		return false
+	}
+	h.seen[id] = now
+	h.deliveries = append(h.deliveries, delivery{id: id, at: now})
+	return true
+}
+
+// forget lets event id be delivered again, after its callbacks failed.
+func (h *Handler) forget(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.seen, id)
+}
@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSecret = []byte("whsec")
+
+func newTestHandler(now *time.Time) *Handler {
+	h, err := NewHandler(Config{
+		Secret: string(testSecret),
+		Now:    func() time.Time { return *now },
+	})
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func deliver(h http.Handler, payload string, signedAt time.Time) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	r.Header.Set(HeaderSignature, Sign(testSecret, []byte(payload), signedAt))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func Test_HandlerDispatch(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	h := newTestHandler(&now)
+
+	var transfer *TransferStateChanged
+	var beneficiary *BeneficiaryVerified
+	var kyc *KYCStatusChanged
+	var batch *BatchCompleted
+	var unhandled []EventType
+	h.OnTransferStateChanged(func(ctx context.Context, e *Event, data *TransferStateChanged) error {
+		transfer = data
+		return nil
+	})
+	h.OnBeneficiaryVerified(func(ctx context.Context, e *Event, data *BeneficiaryVerified) error {
+		beneficiary = data
+		return nil
+	})
+	h.OnKYCStatusChanged(func(ctx context.Context, e *Event, data *KYCStatusChanged) error {
+		kyc = data
+		return nil
+	})
+	h.OnBatchCompleted(func(ctx context.Context, e *Event, data *BatchCompleted) error {
+		batch = data
+		return nil
+	})
+	h.OnUnhandled(func(ctx context.Context, e *Event) error {
+		unhandled = append(unhandled, e.Type)
+		return nil
+	})
+
+	testCases := []struct {
+		desc    string
+		payload string
+	}{
+		{
+			desc: "transfer state changed",
+			payload: `{"id": "evt-1", "type": "transfer.state_changed", "data":
+				{"transfer_uuid": "transfer-1", "state": "completed", "previous_state": "processing"}}`,
+		},
+		{
+			desc: "beneficiary verified",
+			payload: `{"id": "evt-2", "type": "beneficiary.verified", "data":
+				{"beneficiary_uuid": "bene-1", "verified": true}}`,
+		},
+		{
+			desc: "kyc status changed",
+			payload: `{"id": "evt-3", "type": "kyc.status_changed", "data":
+				{"user_uuid": "user-1", "status": "rejected", "reason": "blurry id"}}`,
+		},
+		{
+			desc: "batch completed",
+			payload: `{"id": "evt-4", "type": "batch.completed", "data":
+				{"batch_uuid": "batch-1", "succeeded": 9, "failed": 1}}`,
+		},
+		{
+			desc:    "unknown type",
+			payload: `{"id": "evt-5", "type": "account.frozen", "data": {}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			w := deliver(h, testCase.payload, now)
+			assert.Equal(t, http.StatusNoContent, w.Code)
+		})
+	}
+
+	assert.Equal(t, &TransferStateChanged{TransferUUID: "transfer-1",
+		State: "completed", PreviousState: "processing"}, transfer)
+	assert.Equal(t, &BeneficiaryVerified{BeneficiaryUUID: "bene-1", Verified: true},
+		beneficiary)
+	assert.Equal(t, &KYCStatusChanged{UserUUID: "user-1", Status: "rejected",
+		Reason: "blurry id"}, kyc)
+	assert.Equal(t, &BatchCompleted{BatchUUID: "batch-1", Succeeded: 9, Failed: 1}, batch)
+	assert.Equal(t, []EventType{"account.frozen"}, unhandled)
+}
+
+func Test_HandlerRejections(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := `{"id": "evt-1", "type": "transfer.state_changed", "data": {}}`
+
+	testCases := []struct {
+		desc           string
+		request        func() *http.Request
+		expectedStatus int
+	}{
+		{
+			desc: "wrong method",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/", nil)
+			},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			desc: "unsigned",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "stale",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+				r.Header.Set(HeaderSignature, Sign(testSecret, []byte(payload),
+					now.Add(-time.Hour)))
+				return r
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "not json",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("nope"))
+				r.Header.Set(HeaderSignature, Sign(testSecret, []byte("nope"), now))
+				return r
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "no event id",
+			request: func() *http.Request {
+				payload := `{"type": "transfer.state_changed", "data": {}}`
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+				r.Header.Set(HeaderSignature, Sign(testSecret, []byte(payload), now))
+				return r
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "too large",
+			request: func() *http.Request {
+				big := strings.Repeat(" ", DefaultMaxBodyBytes) + payload
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(big))
+				r.Header.Set(HeaderSignature, Sign(testSecret, []byte(big), now))
+				return r
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			called := false
+			h := newTestHandler(&now)
+			h.On(EventTransferStateChanged, func(ctx context.Context, e *Event) error {
+				called = true
+				return nil
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, testCase.request())
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.False(t, called)
+		})
+	}
+}
+
+func Test_HandlerReplay(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	h := newTestHandler(&now)
+	calls := 0
+	h.On(EventBatchCompleted, func(ctx context.Context, e *Event) error {
+		calls++
+		return nil
+	})
+
+	payload := `{"id": "evt-1", "type": "batch.completed", "data": {}}`
+	assert.Equal(t, http.StatusNoContent, deliver(h, payload, now).Code)
+	assert.Equal(t, http.StatusConflict, deliver(h, payload, now).Code)
+
+	// Once the event is forgotten its signature is too old to be replayed.
+	signedAt := now
+	now = now.Add(DefaultTolerance + time.Second)
+	assert.Equal(t, http.StatusUnauthorized, deliver(h, payload, signedAt).Code)
+	assert.Equal(t, 1, calls)
+
+	// Expired deliveries are dropped when the next one is remembered.
+	now = now.Add(DefaultTolerance)
+	other := `{"id": "evt-2", "type": "batch.completed", "data": {}}`
+	assert.Equal(t, http.StatusNoContent, deliver(h, other, now).Code)
+	assert.Equal(t, map[string]time.Time{"evt-2": now}, h.seen)
+	assert.Len(t, h.deliveries, 1)
+}
+
+func Test_HandlerReplayFutureSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	h := newTestHandler(&now)
+	calls := 0
+	h.On(EventBatchCompleted, func(ctx context.Context, e *Event) error {
+		calls++
+		return nil
+	})
+
+	// A delivery signed ahead of the local clock stays valid for twice the
+	// tolerance, and is remembered as long.
+	payload := `{"id": "evt-1", "type": "batch.completed", "data": {}}`
+	signedAt := now.Add(DefaultTolerance - time.Minute)
+	assert.Equal(t, http.StatusNoContent, deliver(h, payload, signedAt).Code)
+
+	now = now.Add(DefaultTolerance + time.Second)
+	assert.Equal(t, http.StatusConflict, deliver(h, payload, signedAt).Code)
+
+	now = signedAt.Add(DefaultTolerance)
+	assert.Equal(t, http.StatusConflict, deliver(h, payload, signedAt).Code)
+	assert.Equal(t, 1, calls)
+}
+
+func Test_NewHandlerEmptySecret(t *testing.T) {
+	h, err := NewHandler(Config{})
+	assert.Nil(t, h)
+	assert.Equal(t, ErrEmptySecret, err)
+}
+
+func Test_HandlerCallbackFailure(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	h := newTestHandler(&now)
+	fail := true
+	h.OnTransferStateChanged(func(ctx context.Context, e *Event, data *TransferStateChanged) error {
+		if fail {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	payload := `{"id": "evt-1", "type": "transfer.state_changed", "data": {"state": "completed"}}`
+	assert.Equal(t, http.StatusInternalServerError, deliver(h, payload, now).Code)
+
+	// The failed delivery is not remembered, so the retry goes through.
+	fail = false
+	assert.Equal(t, http.StatusNoContent, deliver(h, payload, now).Code)
+
+	invalid := `{"id": "evt-2", "type": "transfer.state_changed", "data": {"state": 1}}`
+	assert.Equal(t, http.StatusBadRequest, deliver(h, invalid, now).Code)
+}
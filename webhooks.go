@@ -0,0 +1,74 @@
+package routefusion
+
+import "context"
+
+// GetWebhook returns a registered webhook.
+func (a *API) GetWebhook(id string) (*WebhookResponse, error) {
+	return a.GetWebhookWithContext(context.Background(), id)
+}
+
+// GetWebhookWithContext is like GetWebhook but binds the request to ctx.
+func (a *API) GetWebhookWithContext(ctx context.Context,
+	id string) (*WebhookResponse, error) {
+	out := &WebhookResponse{}
+	if err := a.do(ctx, getWebhookEndpoint.operation(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateWebhook updates a registered webhook.
+func (a *API) UpdateWebhook(id string, updateInput WebhookUpdateInput) (*WebhookResponse, error) {
+	return a.UpdateWebhookWithContext(context.Background(), id, updateInput)
+}
+
+// UpdateWebhookWithContext is like UpdateWebhook but binds the request to ctx.
+func (a *API) UpdateWebhookWithContext(ctx context.Context,
+	id string, updateInput WebhookUpdateInput) (*WebhookResponse, error) {
+	out := &WebhookResponse{}
+	if err := a.do(ctx, updateWebhookEndpoint.operation(id), updateInput, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IndexWebhooks lists a page of the registered webhooks.
+// The page is selected by opts, nil selects the first one.
+func (a *API) IndexWebhooks(opts *ListOptions) ([]WebhookResponse, error) {
+	return a.IndexWebhooksWithContext(context.Background(), opts)
+}
+
+// IndexWebhooksWithContext is like IndexWebhooks but binds the request to ctx.
+func (a *API) IndexWebhooksWithContext(ctx context.Context,
+	opts *ListOptions) ([]WebhookResponse, error) {
+	var out []WebhookResponse
+	if _, err := a.list(ctx, indexWebhooksEndpoint.operation(), opts, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWebhook registers a new webhook.
+func (a *API) CreateWebhook(createInput WebhookUpdateInput) (*WebhookResponse, error) {
+	return a.CreateWebhookWithContext(context.Background(), createInput)
+}
+
+// CreateWebhookWithContext is like CreateWebhook but binds the request to ctx.
+func (a *API) CreateWebhookWithContext(ctx context.Context,
+	createInput WebhookUpdateInput) (*WebhookResponse, error) {
+	out := &WebhookResponse{}
+	if err := a.do(ctx, createWebhookEndpoint.operation(), createInput, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteWebhook removes a registered webhook.
+func (a *API) DeleteWebhook(id string) error {
+	return a.DeleteWebhookWithContext(context.Background(), id)
+}
+
+// DeleteWebhookWithContext is like DeleteWebhook but binds the request to ctx.
+func (a *API) DeleteWebhookWithContext(ctx context.Context, id string) error {
+	return a.do(ctx, deleteWebhookEndpoint.operation(id), nil, nil)
+}
@@ -0,0 +1,21 @@
+package routefusion
+
+import "context"
+
+// GetWireInstructions returns the instructions to wire funds in the given
+// currency to Routefusion.
+func (a *API) GetWireInstructions(currencyCode string) ([]PaymentInstructions, error) {
+	return a.GetWireInstructionsWithContext(context.Background(), currencyCode)
+}
+
+// GetWireInstructionsWithContext is like GetWireInstructions but binds the
+// request to ctx.
+func (a *API) GetWireInstructionsWithContext(ctx context.Context,
+	currencyCode string) ([]PaymentInstructions, error) {
+	var out []PaymentInstructions
+	op := getWireInstructionsEndpoint.operation(currencyCode)
+	if err := a.do(ctx, op, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}